@@ -66,16 +66,16 @@ variable "check_commands" {
 variable "contacts" {
   description = "Monitoring contacts"
   type = map(object({
-    mail          = string
-    alias         = optional(string, "")
-    pager         = optional(string, "")
-    contact_group = optional(string)
+    mail           = string
+    alias          = optional(string, "")
+    pager          = optional(string, "")
+    contact_groups = optional(set(string))
   }))
   default = {
     "oncall-ops" = {
-      mail          = "oncall@example.com"
-      alias         = "On-call Operations"
-      contact_group = "admins"
+      mail           = "oncall@example.com"
+      alias          = "On-call Operations"
+      contact_groups = ["admins"]
     }
     "dev-lead" = {
       mail  = "dev-lead@example.com"
@@ -94,11 +94,11 @@ resource "eon_contact_group" "ops" {
 resource "eon_contact" "this" {
   for_each = var.contacts
 
-  name          = each.key
-  mail          = each.value.mail
-  alias         = each.value.alias
-  pager         = each.value.pager
-  contact_group = each.value.contact_group
+  name           = each.key
+  mail           = each.value.mail
+  alias          = each.value.alias
+  pager          = each.value.pager
+  contact_groups = each.value.contact_groups
 }
 
 # ─── Check commands (from variable map) ─────────────────────────
@@ -114,17 +114,23 @@ resource "eon_command" "this" {
 resource "eon_host" "this" {
   for_each = var.servers
 
-  name          = each.key
-  ip            = each.value.ip
-  alias         = each.value.alias
-  template      = each.value.template
-  contact_group = eon_contact_group.ops.name
+  name           = each.key
+  ip             = each.value.ip
+  alias          = each.value.alias
+  templates      = [each.value.template]
+  contact_groups = [eon_contact_group.ops.name]
 }
 
 # ─── Export configuration (last step — reloads Nagios) ──────────
 resource "eon_export_configuration" "apply" {
   job_name = "terraform-apply"
 
+  triggers = {
+    hosts    = sha1(jsonencode(eon_host.this))
+    commands = sha1(jsonencode(eon_command.this))
+    contacts = sha1(jsonencode(eon_contact.this))
+  }
+
   depends_on = [
     eon_host.this,
     eon_command.this,
@@ -140,5 +146,8 @@ data "eon_host" "web" {
 }
 
 output "web_host_info" {
-  value = data.eon_host.web.result_json
+  value = {
+    ip        = data.eon_host.web.ip
+    templates = data.eon_host.web.templates
+  }
 }
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// readCache holds per-object-kind listNagiosObjects snapshots so that a
+// refresh of N resources costs one list call per kind instead of N
+// individual get calls, and the list data sources share the same call.
+type readCache struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	kinds map[string]*snapshot
+}
+
+type snapshot struct {
+	mu       sync.Mutex
+	fetched  time.Time
+	response *APIResponse
+	objects  map[string]map[string]interface{}
+}
+
+// EnableReadCache turns on the snapshot cache for unfiltered
+// ListNagiosObjects calls and for GetHost, GetContact and GetCommand.
+// A ttl <= 0 disables it again.
+func (c *Client) EnableReadCache(ttl time.Duration) {
+	if ttl <= 0 {
+		c.cache = nil
+		return
+	}
+	c.cache = &readCache{ttl: ttl, kinds: map[string]*snapshot{}}
+}
+
+func (rc *readCache) entry(kind string) *snapshot {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	s, ok := rc.kinds[kind]
+	if !ok {
+		s = &snapshot{}
+		rc.kinds[kind] = s
+	}
+	return s
+}
+
+// invalidate drops every snapshot; called after any mutating request.
+func (rc *readCache) invalidate() {
+	rc.mu.Lock()
+	rc.kinds = map[string]*snapshot{}
+	rc.mu.Unlock()
+}
+
+// cachedList returns the listNagiosObjects response for kind.
+func (c *Client) cachedList(kind string) (*APIResponse, error) {
+	s, err := c.snapshot(kind)
+	if err != nil {
+		return nil, err
+	}
+	return s.response, nil
+}
+
+// cached serves a single object from the snapshot of its kind. A miss, or
+// a failed fetch, reports ok=false so the caller falls back to the get
+// endpoint: objects created since the last export are not in livestatus yet.
+func (c *Client) cached(kind, name string) (*APIResponse, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+	s, err := c.snapshot(kind)
+	if err != nil {
+		return nil, false
+	}
+	obj, ok := s.objects[name]
+	if !ok {
+		return nil, false
+	}
+	return &APIResponse{HTTPCode: "200 OK", Result: []interface{}{obj}}, true
+}
+
+// snapshot returns the snapshot of kind, fetching it if it is missing or
+// older than the TTL. Concurrent callers for the same kind wait for a
+// single fetch; failed fetches are not cached. The returned snapshot is
+// never modified again.
+func (c *Client) snapshot(kind string) (*snapshot, error) {
+	s := c.cache.entry(kind)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.response == nil || time.Since(s.fetched) > c.cache.ttl {
+		r, err := c.Post("listNagiosObjects", map[string]interface{}{"object": kind})
+		if err != nil {
+			return nil, err
+		}
+		objects := map[string]map[string]interface{}{}
+		for _, obj := range ResultObjects(r) {
+			if n := objectName(obj); n != "" {
+				objects[n] = obj
+			}
+		}
+		s.fetched, s.response, s.objects = time.Now(), r, objects
+	}
+	return &snapshot{fetched: s.fetched, response: s.response, objects: s.objects}, nil
+}
+
+// isReadEndpoint reports whether an endpoint only reads data.
+func isReadEndpoint(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "get") || strings.HasPrefix(endpoint, "list")
+}
+
+func objectName(obj map[string]interface{}) string {
+	for _, k := range []string{"name", "host_name", "contact_name", "command_name", "contactgroup_name"} {
+		if s, ok := obj[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
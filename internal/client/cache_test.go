@@ -0,0 +1,58 @@
+package client_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/ktoulliou/terraform-provider-eon/internal/eontest"
+)
+
+func TestReadCache(t *testing.T) {
+	s := eontest.NewServer()
+	defer s.Close()
+	c := s.Client()
+	c.EnableReadCache(time.Minute)
+
+	s.Put(eontest.Hosts, "web01", eontest.Object{"address": "10.0.0.1"})
+	for i := 0; i < 3; i++ {
+		if hosts, err := c.ListHosts(); err != nil || len(hosts) != 1 {
+			t.Fatalf("ListHosts: got %v, %v", hosts, err)
+		}
+	}
+	if n := s.Calls("listNagiosObjects"); n != 1 {
+		t.Errorf("listNagiosObjects called %d times, want 1", n)
+	}
+
+	// Gets are served from the same snapshot.
+	for i := 0; i < 3; i++ {
+		if h, err := c.GetHost("web01"); err != nil || client.FirstObject(h)["address"] != "10.0.0.1" {
+			t.Fatalf("GetHost: got %v, %v", h, err)
+		}
+	}
+	if n := s.Calls("getHost"); n != 0 {
+		t.Errorf("getHost called %d times, want 0", n)
+	}
+
+	// A name missing from the snapshot falls back to the get endpoint.
+	s.Put(eontest.Hosts, "web02", eontest.Object{"address": "10.0.0.2"})
+	if h, err := c.GetHost("web02"); err != nil || client.FirstObject(h)["address"] != "10.0.0.2" {
+		t.Fatalf("GetHost of a host missing from the snapshot: got %v, %v", h, err)
+	}
+	if n := s.Calls("getHost"); n != 1 {
+		t.Errorf("getHost called %d times, want 1", n)
+	}
+	s.Delete(eontest.Hosts, "web01")
+	s.Delete(eontest.Hosts, "web02")
+
+	// Any mutation drops the snapshot.
+	if _, err := c.AddCommand("check_ping", "$USER1$/check_ping", ""); err != nil {
+		t.Fatalf("AddCommand: %s", err)
+	}
+	if hosts, err := c.ListHosts(); err != nil || len(hosts) != 0 {
+		t.Fatalf("ListHosts after mutation: got %v, %v", hosts, err)
+	}
+	if n := s.Calls("listNagiosObjects"); n != 2 {
+		t.Errorf("listNagiosObjects called %d times, want 2", n)
+	}
+}
@@ -0,0 +1,218 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Cassettes capture the EONAPI traffic of a run so a customer issue can be
+// replayed without a server: EON_RECORD=file records, EON_REPLAY=file
+// replays. Credentials never reach the file: the query string (username and
+// apiKey) is dropped, secret-looking JSON fields are redacted, and so are
+// the values of host custom variables, which often hold secrets under
+// names nothing can guess (_DB_PASS, _SNMP_AUTH, ...).
+
+const redacted = "REDACTED"
+
+// Interaction is one recorded request/response pair.
+type Interaction struct {
+	Method       string `json:"method"`
+	Endpoint     string `json:"endpoint"`
+	RequestBody  string `json:"request_body,omitempty"`
+	Status       int    `json:"status"`
+	ResponseBody string `json:"response_body"`
+}
+
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper that forwards requests to Next and
+// appends every exchange to the cassette file at Path.
+type Recorder struct {
+	Path string
+	Next http.RoundTripper
+
+	mu       sync.Mutex
+	recorded cassette
+}
+
+// NewRecorder returns a Recorder writing to path.
+func NewRecorder(path string, next http.RoundTripper) *Recorder {
+	return &Recorder{Path: path, Next: next}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := drainBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recorded.Interactions = append(r.recorded.Interactions, Interaction{
+		Method:       req.Method,
+		Endpoint:     path.Base(req.URL.Path),
+		RequestBody:  redactJSON(reqBody),
+		Status:       resp.StatusCode,
+		ResponseBody: redactJSON(respBody),
+	})
+	// Rewritten after every exchange: the provider process may be killed
+	// without notice.
+	b, err := json.MarshalIndent(&r.recorded, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(r.Path, b, 0o600); err != nil {
+		return nil, fmt.Errorf("write cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper answering requests from a cassette file.
+// Each recorded interaction is served once, in order, to the first request
+// with the same method, endpoint and (redacted) body.
+type Replayer struct {
+	Path string
+
+	mu     sync.Mutex
+	loaded bool
+	items  []Interaction
+	used   []bool
+}
+
+// NewReplayer returns a Replayer reading path on first use.
+func NewReplayer(path string) *Replayer {
+	return &Replayer{Path: path}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := path.Base(req.URL.Path)
+	body := redactJSON(reqBody)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.loaded {
+		b, err := os.ReadFile(r.Path)
+		if err != nil {
+			return nil, fmt.Errorf("read cassette: %w", err)
+		}
+		var c cassette
+		if err := json.Unmarshal(b, &c); err != nil {
+			return nil, fmt.Errorf("parse cassette %s: %w", r.Path, err)
+		}
+		r.items, r.used, r.loaded = c.Interactions, make([]bool, len(c.Interactions)), true
+	}
+
+	for i, it := range r.items {
+		if r.used[i] || it.Method != req.Method || it.Endpoint != endpoint || it.RequestBody != body {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", it.Status, http.StatusText(it.Status)),
+			StatusCode:    it.Status,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(strings.NewReader(it.ResponseBody)),
+			ContentLength: int64(len(it.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette %s has no unused interaction for %s %s %s", r.Path, req.Method, endpoint, body)
+}
+
+// drainBody reads a body and replaces it with an identical unread copy.
+func drainBody(b *io.ReadCloser) (string, error) {
+	if *b == nil || *b == http.NoBody {
+		return "", nil
+	}
+	data, err := io.ReadAll(*b)
+	(*b).Close()
+	if err != nil {
+		return "", err
+	}
+	*b = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
+
+// redactJSON masks secret-looking fields of a JSON document; non-JSON
+// payloads are returned unchanged.
+func redactJSON(s string) string {
+	if s == "" {
+		return s
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return s
+	}
+	return string(b)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			vars, isVars := e.(map[string]interface{})
+			switch {
+			case isSecretKey(k):
+				t[k] = redacted
+			case isCustomVariablesKey(k) && isVars:
+				// Names are kept for debugging; deleteCustomArgumentsToHost
+				// sends only names, as a list, and is left alone.
+				for name := range vars {
+					vars[name] = redacted
+				}
+			default:
+				t[k] = redactValue(e)
+			}
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = redactValue(e)
+		}
+	}
+	return v
+}
+
+// isCustomVariablesKey reports whether k holds host custom variables, in
+// a request (customArguments) or a response (custom_variables).
+func isCustomVariablesKey(k string) bool {
+	switch strings.ToLower(k) {
+	case "customarguments", "custom_arguments", "custom_variables":
+		return true
+	}
+	return false
+}
+
+func isSecretKey(k string) bool {
+	k = strings.ToLower(k)
+	for _, s := range []string{"password", "apikey", "api_key", "secret", "token", "community"} {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
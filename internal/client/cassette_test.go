@@ -0,0 +1,73 @@
+package client_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/ktoulliou/terraform-provider-eon/internal/eontest"
+)
+
+func TestCassetteRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	host := map[string]interface{}{"hostName": "web01", "hostIp": "10.0.0.1", "snmpPassword": "s3cret"}
+
+	s := eontest.NewServer()
+	t.Setenv("EON_RECORD", path)
+	rec := client.NewClient(s.URL, eontest.Username, eontest.APIKey, false)
+	if err := rec.CheckAuth(); err != nil {
+		t.Fatalf("CheckAuth: %s", err)
+	}
+	if _, err := rec.CreateHost(host); err != nil {
+		t.Fatalf("CreateHost: %s", err)
+	}
+	if _, err := rec.AddCustomArgumentsToHost("web01", map[string]string{"_DB_PASS": "hunter2"}, false); err != nil {
+		t.Fatalf("AddCustomArgumentsToHost: %s", err)
+	}
+	if _, err := rec.GetHost("web01"); err != nil {
+		t.Fatalf("GetHost: %s", err)
+	}
+	if _, err := rec.GetHost("missing"); err == nil {
+		t.Fatal("GetHost missing: expected error")
+	}
+	s.Close()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cassette: %s", err)
+	}
+	for _, secret := range []string{eontest.APIKey, "s3cret", "hunter2"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("cassette leaks %q:\n%s", secret, b)
+		}
+	}
+
+	t.Setenv("EON_RECORD", "")
+	t.Setenv("EON_REPLAY", path)
+	rep := client.NewClient("http://eon.invalid", "someone", "other-key", false)
+	if err := rep.CheckAuth(); err != nil {
+		t.Fatalf("replayed CheckAuth: %s", err)
+	}
+	if _, err := rep.CreateHost(host); err != nil {
+		t.Fatalf("replayed CreateHost: %s", err)
+	}
+	if _, err := rep.AddCustomArgumentsToHost("web01", map[string]string{"_DB_PASS": "hunter2"}, false); err != nil {
+		t.Fatalf("replayed AddCustomArgumentsToHost: %s", err)
+	}
+	r, err := rep.GetHost("web01")
+	if err != nil {
+		t.Fatalf("replayed GetHost: %s", err)
+	}
+	if v := client.DecodeHost(client.FirstObject(r)).CustomVariables["_DB_PASS"]; v != "REDACTED" {
+		t.Errorf("replayed custom variable = %q, want REDACTED", v)
+	}
+	_, err = rep.GetHost("missing")
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("replayed GetHost missing: got %v, want 404 error", err)
+	}
+	if _, err := rep.GetHost("web01"); err == nil || !strings.Contains(err.Error(), "no unused interaction") {
+		t.Fatalf("unrecorded request: got %v, want cassette miss", err)
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,6 +21,13 @@ type Client struct {
 	Username   string
 	APIKey     string
 	HTTPClient *http.Client
+
+	cache *readCache
+
+	// mutations counts mutating calls; exported is the count covered by
+	// the last successful exportConfiguration.
+	mutations atomic.Int64
+	exported  atomic.Int64
 }
 
 // APIResponse is the generic shape returned by every EONAPI endpoint.
@@ -31,9 +41,15 @@ type APIResponse struct {
 
 // NewClient creates a new EONAPI HTTP client.
 func NewClient(baseURL, username, apiKey string, insecure bool) *Client {
-	tr := &http.Transport{
+	var tr http.RoundTripper = &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}, //nolint:gosec
 	}
+	// EON_REPLAY wins over EON_RECORD: replaying never touches the network.
+	if p := os.Getenv("EON_REPLAY"); p != "" {
+		tr = NewReplayer(p)
+	} else if p := os.Getenv("EON_RECORD"); p != "" {
+		tr = NewRecorder(p, tr)
+	}
 	return &Client{
 		BaseURL:  strings.TrimRight(baseURL, "/"),
 		Username: username,
@@ -73,12 +89,40 @@ func (c *Client) Post(endpoint string, body interface{}) (*APIResponse, error) {
 		return nil, fmt.Errorf("request %s: %w", endpoint, err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	var pending int64
+	if !isReadEndpoint(endpoint) {
+		switch {
+		case endpoint == "exportConfiguration":
+			pending = c.mutations.Load()
+		case !runtimeEndpoints[endpoint]:
+			c.mutations.Add(1)
+		}
+		if c.cache != nil {
+			defer c.cache.invalidate()
+		}
+	}
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("POST %s: %w", endpoint, err)
 	}
 	defer resp.Body.Close()
-	return decodeResp(resp)
+	r, err := decodeResp(resp)
+	if err == nil && endpoint == "exportConfiguration" {
+		c.markExported(pending)
+	}
+	return r, err
+}
+
+// markExported records that the mutations counted before an export
+// succeeded are loaded. Mutations made while the export ran stay pending,
+// and a slower export of an older count never moves the mark back.
+func (c *Client) markExported(n int64) {
+	for {
+		cur := c.exported.Load()
+		if cur >= n || c.exported.CompareAndSwap(cur, n) {
+			return
+		}
+	}
 }
 
 func decodeResp(resp *http.Response) (*APIResponse, error) {
@@ -94,11 +138,46 @@ func decodeResp(resp *http.Response) (*APIResponse, error) {
 		return nil, fmt.Errorf("status %d, raw: %s", resp.StatusCode, string(body))
 	}
 	if resp.StatusCode >= 400 {
-		return &r, fmt.Errorf("API %d: %s", resp.StatusCode, r.HTTPCode)
+		return &r, &apiError{code: resp.StatusCode, httpCode: r.HTTPCode}
 	}
 	return &r, nil
 }
 
+// ErrNotFound matches, with errors.Is, the errors of calls about an object
+// EON does not know.
+var ErrNotFound = errors.New("not found")
+
+// apiError is an error status returned by EONAPI.
+type apiError struct {
+	code     int
+	httpCode string
+}
+
+func (e *apiError) Error() string { return fmt.Sprintf("API %d: %s", e.code, e.httpCode) }
+
+func (e *apiError) Is(target error) bool {
+	return target == ErrNotFound && e.code == http.StatusNotFound
+}
+
+// runtimeEndpoints act on the running Nagios rather than on the Lilac
+// configuration, so they never leave anything to export.
+var runtimeEndpoints = map[string]bool{
+	"createHostDowntime":           true,
+	"createServiceDowntime":        true,
+	"deleteHostDowntime":           true,
+	"deleteServiceDowntime":        true,
+	"acknowledgeHostProblem":       true,
+	"acknowledgeServiceProblem":    true,
+	"removeHostAcknowledgement":    true,
+	"removeServiceAcknowledgement": true,
+}
+
+// Mutated reports whether a mutating call was made since the last
+// exportConfiguration, i.e. whether EON holds changes Nagios has not loaded.
+func (c *Client) Mutated() bool {
+	return c.mutations.Load() > c.exported.Load()
+}
+
 // CheckAuth validates credentials against getAuthenticationStatus.
 func (c *Client) CheckAuth() error {
 	r, err := c.Get("getAuthenticationStatus")
@@ -111,6 +190,84 @@ func (c *Client) CheckAuth() error {
 	return nil
 }
 
+// ─── Nagios objects (livestatus) ──────────────────────────────────
+
+// ListNagiosObjects lists every object of a livestatus table (hosts,
+// commands, contacts, contactgroups, ...). Optional filters are raw
+// livestatus "Filter:" lines. Unfiltered lists are served from the read
+// cache when it is enabled.
+func (c *Client) ListNagiosObjects(object string, filters ...string) (*APIResponse, error) {
+	if c.cache != nil && len(filters) == 0 {
+		return c.cachedList(object)
+	}
+	body := map[string]interface{}{"object": object}
+	if len(filters) > 0 {
+		body["filters"] = filters
+	}
+	return c.Post("listNagiosObjects", body)
+}
+
+// LivestatusFilter builds a livestatus "Filter:" line. Newlines would let a
+// value inject extra livestatus headers, so they are stripped.
+func LivestatusFilter(column, op, value string) string {
+	value = strings.NewReplacer("\n", "", "\r", "").Replace(value)
+	return fmt.Sprintf("Filter: %s %s %s", column, op, value)
+}
+
+// ResultObjects flattens an API result into a list of objects. EONAPI
+// returns either a plain list, a single object, or a map of backend name to
+// list depending on the endpoint.
+func ResultObjects(r *APIResponse) []map[string]interface{} {
+	if r == nil {
+		return nil
+	}
+	return flattenObjects(r.Result)
+}
+
+func flattenObjects(v interface{}) []map[string]interface{} {
+	var out []map[string]interface{}
+	switch t := v.(type) {
+	case []interface{}:
+		for _, e := range t {
+			out = append(out, flattenObjects(e)...)
+		}
+	case map[string]interface{}:
+		nested := true
+		for _, e := range t {
+			if _, ok := e.([]interface{}); !ok {
+				nested = false
+				break
+			}
+		}
+		if nested && len(t) > 0 {
+			for _, e := range t {
+				out = append(out, flattenObjects(e)...)
+			}
+		} else {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// ListNagiosStates returns current livestatus states of "hosts" or
+// "services", narrowed by raw livestatus "Filter:" lines.
+func (c *Client) ListNagiosStates(object string, filters ...string) ([]State, error) {
+	body := map[string]interface{}{"object": object}
+	if len(filters) > 0 {
+		body["filters"] = filters
+	}
+	r, err := c.Post("listNagiosStates", body)
+	if err != nil {
+		return nil, err
+	}
+	var states []State
+	for _, obj := range ResultObjects(r) {
+		states = append(states, DecodeState(obj))
+	}
+	return states, nil
+}
+
 // ─── Host ─────────────────────────────────────────────────────────
 
 func (c *Client) CreateHost(body map[string]interface{}) (*APIResponse, error) {
@@ -118,9 +275,25 @@ func (c *Client) CreateHost(body map[string]interface{}) (*APIResponse, error) {
 }
 
 func (c *Client) GetHost(name string) (*APIResponse, error) {
+	if r, ok := c.cached("hosts", name); ok {
+		return r, nil
+	}
 	return c.Post("getHost", map[string]string{"hostName": name})
 }
 
+// ListHosts returns every host known to Nagios.
+func (c *Client) ListHosts() ([]Host, error) {
+	r, err := c.ListNagiosObjects("hosts")
+	if err != nil {
+		return nil, err
+	}
+	var hosts []Host
+	for _, obj := range ResultObjects(r) {
+		hosts = append(hosts, DecodeHost(obj))
+	}
+	return hosts, nil
+}
+
 func (c *Client) DeleteHost(name string, export bool) (*APIResponse, error) {
 	return c.Post("deleteHost", map[string]interface{}{
 		"hostName": name, "exportConfiguration": export,
@@ -133,6 +306,77 @@ func (c *Client) AddHostTemplateToHost(tpl, host string, export bool) (*APIRespo
 	})
 }
 
+func (c *Client) DeleteHostTemplateToHost(tpl, host string, export bool) (*APIResponse, error) {
+	return c.Post("deleteHostTemplateToHost", map[string]interface{}{
+		"templateHostName": tpl, "hostName": host, "exportConfiguration": export,
+	})
+}
+
+func (c *Client) AddContactToHost(contact, host string, export bool) (*APIResponse, error) {
+	return c.Post("addContactToHost", map[string]interface{}{
+		"contactName": contact, "hostName": host, "exportConfiguration": export,
+	})
+}
+
+func (c *Client) DeleteContactToHost(contact, host string, export bool) (*APIResponse, error) {
+	return c.Post("deleteContactToHost", map[string]interface{}{
+		"contactName": contact, "hostName": host, "exportConfiguration": export,
+	})
+}
+
+func (c *Client) AddContactGroupToHost(group, host string, export bool) (*APIResponse, error) {
+	return c.Post("addContactGroupToHost", map[string]interface{}{
+		"contactGroupName": group, "hostName": host, "exportConfiguration": export,
+	})
+}
+
+func (c *Client) DeleteContactGroupToHost(group, host string, export bool) (*APIResponse, error) {
+	return c.Post("deleteContactGroupToHost", map[string]interface{}{
+		"contactGroupName": group, "hostName": host, "exportConfiguration": export,
+	})
+}
+
+func (c *Client) AddParentToHost(parent, host string, export bool) (*APIResponse, error) {
+	return c.Post("addParentToHost", map[string]interface{}{
+		"parentName": parent, "hostName": host, "exportConfiguration": export,
+	})
+}
+
+func (c *Client) DeleteParentToHost(parent, host string, export bool) (*APIResponse, error) {
+	return c.Post("deleteParentToHost", map[string]interface{}{
+		"parentName": parent, "hostName": host, "exportConfiguration": export,
+	})
+}
+
+// ModifyHostParameters sets Nagios directives (max_check_attempts,
+// check_interval, ...) on a host. An empty value removes the host's own
+// value so that it inherits the one of its templates again.
+func (c *Client) ModifyHostParameters(host string, params map[string]string, export bool) (*APIResponse, error) {
+	return c.Post("modifyHostParameters", map[string]interface{}{
+		"hostName": host, "parameters": params, "exportConfiguration": export,
+	})
+}
+
+// AddCustomArgumentsToHost sets new custom variables (_NAME: value) on a
+// host; ModifyCustomArgumentsToHost changes existing ones.
+func (c *Client) AddCustomArgumentsToHost(host string, args map[string]string, export bool) (*APIResponse, error) {
+	return c.Post("addCustomArgumentsToHost", map[string]interface{}{
+		"hostName": host, "customArguments": args, "exportConfiguration": export,
+	})
+}
+
+func (c *Client) ModifyCustomArgumentsToHost(host string, args map[string]string, export bool) (*APIResponse, error) {
+	return c.Post("modifyCustomArgumentsToHost", map[string]interface{}{
+		"hostName": host, "customArguments": args, "exportConfiguration": export,
+	})
+}
+
+func (c *Client) DeleteCustomArgumentsToHost(host string, names []string, export bool) (*APIResponse, error) {
+	return c.Post("deleteCustomArgumentsToHost", map[string]interface{}{
+		"hostName": host, "customArguments": names, "exportConfiguration": export,
+	})
+}
+
 // ─── Command (check) ──────────────────────────────────────────────
 
 func (c *Client) AddCommand(name, line, desc string) (*APIResponse, error) {
@@ -142,6 +386,9 @@ func (c *Client) AddCommand(name, line, desc string) (*APIResponse, error) {
 }
 
 func (c *Client) GetCommand(name string) (*APIResponse, error) {
+	if r, ok := c.cached("commands", name); ok {
+		return r, nil
+	}
 	return c.Post("getCommand", map[string]string{"commandName": name})
 }
 
@@ -163,9 +410,25 @@ func (c *Client) CreateContact(body map[string]interface{}) (*APIResponse, error
 }
 
 func (c *Client) GetContact(name string) (*APIResponse, error) {
+	if r, ok := c.cached("contacts", name); ok {
+		return r, nil
+	}
 	return c.Post("getContact", map[string]interface{}{"contactName": name})
 }
 
+// ListContacts returns every contact known to Nagios.
+func (c *Client) ListContacts() ([]Contact, error) {
+	r, err := c.ListNagiosObjects("contacts")
+	if err != nil {
+		return nil, err
+	}
+	var contacts []Contact
+	for _, obj := range ResultObjects(r) {
+		contacts = append(contacts, DecodeContact(obj))
+	}
+	return contacts, nil
+}
+
 func (c *Client) ModifyContact(body map[string]interface{}) (*APIResponse, error) {
 	return c.Post("modifyContact", body)
 }
@@ -174,6 +437,18 @@ func (c *Client) DeleteContact(name string) (*APIResponse, error) {
 	return c.Post("deleteContact", map[string]string{"contactName": name})
 }
 
+func (c *Client) AddContactGroupToContact(group, contact string, export bool) (*APIResponse, error) {
+	return c.Post("addContactGroupToContact", map[string]interface{}{
+		"contactGroupName": group, "contactName": contact, "exportConfiguration": export,
+	})
+}
+
+func (c *Client) DeleteContactGroupToContact(group, contact string, export bool) (*APIResponse, error) {
+	return c.Post("deleteContactGroupToContact", map[string]interface{}{
+		"contactGroupName": group, "contactName": contact, "exportConfiguration": export,
+	})
+}
+
 // ─── Contact Group ────────────────────────────────────────────────
 
 func (c *Client) CreateContactGroup(name, desc string, export bool) (*APIResponse, error) {
@@ -186,6 +461,19 @@ func (c *Client) GetContactGroup(name string) (*APIResponse, error) {
 	return c.Post("getContactGroup", map[string]interface{}{"contactGroupName": name})
 }
 
+// ListContactGroups returns every contact group known to Nagios.
+func (c *Client) ListContactGroups() ([]ContactGroup, error) {
+	r, err := c.ListNagiosObjects("contactgroups")
+	if err != nil {
+		return nil, err
+	}
+	var groups []ContactGroup
+	for _, obj := range ResultObjects(r) {
+		groups = append(groups, DecodeContactGroup(obj))
+	}
+	return groups, nil
+}
+
 func (c *Client) ModifyContactGroup(body map[string]interface{}) (*APIResponse, error) {
 	return c.Post("modifyContactGroup", body)
 }
@@ -194,8 +482,280 @@ func (c *Client) DeleteContactGroup(name string) (*APIResponse, error) {
 	return c.Post("deleteContactGroup", map[string]string{"contactGroupName": name})
 }
 
+// ─── EON user ─────────────────────────────────────────────────────
+
+func (c *Client) CreateUser(body map[string]interface{}) (*APIResponse, error) {
+	return c.Post("createUser", body)
+}
+
+func (c *Client) GetUser(name string) (*APIResponse, error) {
+	return c.Post("getUser", map[string]interface{}{"userName": name})
+}
+
+func (c *Client) ModifyUser(body map[string]interface{}) (*APIResponse, error) {
+	return c.Post("modifyUser", body)
+}
+
+func (c *Client) DeleteUser(name string) (*APIResponse, error) {
+	return c.Post("deleteUser", map[string]string{"userName": name})
+}
+
+// ─── EON user group ───────────────────────────────────────────────
+
+func (c *Client) CreateUserGroup(body map[string]interface{}) (*APIResponse, error) {
+	return c.Post("createUserGroup", body)
+}
+
+func (c *Client) GetUserGroup(name string) (*APIResponse, error) {
+	return c.Post("getUserGroup", map[string]interface{}{"groupName": name})
+}
+
+func (c *Client) ModifyUserGroup(body map[string]interface{}) (*APIResponse, error) {
+	return c.Post("modifyUserGroup", body)
+}
+
+func (c *Client) DeleteUserGroup(name string) (*APIResponse, error) {
+	return c.Post("deleteUserGroup", map[string]string{"groupName": name})
+}
+
+// ─── Timeperiod ───────────────────────────────────────────────────
+
+func (c *Client) CreateTimeperiod(body map[string]interface{}) (*APIResponse, error) {
+	return c.Post("createTimeperiod", body)
+}
+
+func (c *Client) GetTimeperiod(name string) (*APIResponse, error) {
+	return c.Post("getTimeperiod", map[string]interface{}{"timeperiodName": name})
+}
+
+func (c *Client) ModifyTimeperiod(body map[string]interface{}) (*APIResponse, error) {
+	return c.Post("modifyTimeperiod", body)
+}
+
+func (c *Client) DeleteTimeperiod(name string) (*APIResponse, error) {
+	return c.Post("deleteTimeperiod", map[string]string{"timeperiodName": name})
+}
+
+// ─── Downtime ─────────────────────────────────────────────────────
+
+// CreateDowntime schedules a host downtime, or a service downtime when
+// d.ServiceDescription is set. EONAPI does not return the new downtime id;
+// use FindDowntime to look it up.
+func (c *Client) CreateDowntime(d Downtime) (*APIResponse, error) {
+	body := map[string]interface{}{
+		"hostName":  d.HostName,
+		"startTime": d.StartTime,
+		"endTime":   d.EndTime,
+		"fixed":     d.Fixed,
+		"duration":  d.Duration,
+		"author":    d.Author,
+		"comment":   d.Comment,
+	}
+	if d.ServiceDescription == "" {
+		return c.Post("createHostDowntime", body)
+	}
+	body["serviceName"] = d.ServiceDescription
+	return c.Post("createServiceDowntime", body)
+}
+
+// ListDowntimes returns the active and pending downtimes of a host,
+// including those of its services.
+func (c *Client) ListDowntimes(host string) ([]Downtime, error) {
+	r, err := c.ListNagiosObjects("downtimes", LivestatusFilter("host_name", "=", host))
+	if err != nil {
+		return nil, err
+	}
+	var out []Downtime
+	for _, obj := range ResultObjects(r) {
+		out = append(out, DecodeDowntime(obj))
+	}
+	return out, nil
+}
+
+// GetDowntime returns the downtime with the given id, or nil once it has
+// expired or been cancelled.
+func (c *Client) GetDowntime(host string, id int64) (*Downtime, error) {
+	all, err := c.ListDowntimes(host)
+	if err != nil {
+		return nil, err
+	}
+	for i := range all {
+		if all[i].ID == id {
+			return &all[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// FindDowntime returns the downtime matching the target, window and comment
+// of d, or nil if Nagios has not registered it (yet).
+func (c *Client) FindDowntime(d Downtime) (*Downtime, error) {
+	all, err := c.ListDowntimes(d.HostName)
+	if err != nil {
+		return nil, err
+	}
+	var found *Downtime
+	for i := range all {
+		e := all[i]
+		if e.ServiceDescription == d.ServiceDescription && e.StartTime == d.StartTime &&
+			e.EndTime == d.EndTime && e.Comment == d.Comment {
+			if found == nil || e.ID > found.ID {
+				found = &all[i]
+			}
+		}
+	}
+	return found, nil
+}
+
+// DeleteDowntime cancels a host or service downtime.
+func (c *Client) DeleteDowntime(d Downtime) (*APIResponse, error) {
+	if d.ServiceDescription == "" {
+		return c.Post("deleteHostDowntime", map[string]interface{}{"idDowntime": d.ID})
+	}
+	return c.Post("deleteServiceDowntime", map[string]interface{}{"idDowntime": d.ID})
+}
+
+// ─── Acknowledgement ──────────────────────────────────────────────
+
+// Acknowledgement acknowledges the current problem of a host, or of a
+// service when ServiceDescription is set.
+type Acknowledgement struct {
+	HostName           string
+	ServiceDescription string
+	Sticky             bool
+	Notify             bool
+	Persistent         bool
+	Author             string
+	Comment            string
+}
+
+// Acknowledge sends an acknowledgement for a host or service problem.
+func (c *Client) Acknowledge(a Acknowledgement) (*APIResponse, error) {
+	body := map[string]interface{}{
+		"hostName":   a.HostName,
+		"sticky":     a.Sticky,
+		"notify":     a.Notify,
+		"persistent": a.Persistent,
+		"author":     a.Author,
+		"comment":    a.Comment,
+	}
+	if a.ServiceDescription == "" {
+		return c.Post("acknowledgeHostProblem", body)
+	}
+	body["serviceName"] = a.ServiceDescription
+	return c.Post("acknowledgeServiceProblem", body)
+}
+
+// RemoveAcknowledgement clears the acknowledgement of a host or service.
+func (c *Client) RemoveAcknowledgement(host, service string) (*APIResponse, error) {
+	if service == "" {
+		return c.Post("removeHostAcknowledgement", map[string]string{"hostName": host})
+	}
+	return c.Post("removeServiceAcknowledgement", map[string]string{
+		"hostName": host, "serviceName": service,
+	})
+}
+
+// GetState returns the livestatus state of a host, or of one of its services
+// when service is set; nil if it does not exist.
+func (c *Client) GetState(host, service string) (*State, error) {
+	var states []State
+	var err error
+	if service == "" {
+		states, err = c.ListNagiosStates("hosts", LivestatusFilter("name", "=", host))
+	} else {
+		states, err = c.ListNagiosStates("services",
+			LivestatusFilter("host_name", "=", host), LivestatusFilter("description", "=", service))
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range states {
+		if states[i].HostName == host && states[i].Description == service {
+			return &states[i], nil
+		}
+	}
+	return nil, nil
+}
+
 // ─── Export ───────────────────────────────────────────────────────
 
 func (c *Client) ExportConfiguration(job string) (*APIResponse, error) {
 	return c.Post("exportConfiguration", map[string]string{"JobName": job})
 }
+
+// ExportJob is the status of a Lilac export job. ID and StartTime identify
+// the run: getExportJob reports the latest run of a job name, which is the
+// previous one until the export just triggered has been picked up.
+type ExportJob struct {
+	ID        int64
+	Name      string
+	StartTime int64 // unix seconds
+	Status    string
+	Output    string
+}
+
+// Supersedes reports whether j is a later run than prev, the run reported
+// before the export was triggered (nil if the job never ran).
+func (j ExportJob) Supersedes(prev *ExportJob) bool {
+	if prev == nil {
+		return true
+	}
+	if j.ID != 0 && prev.ID != 0 {
+		return j.ID > prev.ID
+	}
+	return j.StartTime > prev.StartTime
+}
+
+// Done reports whether the job has stopped running, successfully or not.
+func (j ExportJob) Done() bool {
+	switch strings.ToLower(j.Status) {
+	case "finished", "complete", "completed", "failed", "error":
+		return true
+	}
+	return false
+}
+
+// Failed reports whether the job ended in error.
+func (j ExportJob) Failed() bool {
+	switch strings.ToLower(j.Status) {
+	case "failed", "error":
+		return true
+	}
+	return false
+}
+
+// GetExportJob returns the latest export job with the given name.
+func (c *Client) GetExportJob(job string) (*ExportJob, error) {
+	r, err := c.Post("getExportJob", map[string]string{"JobName": job})
+	if err != nil {
+		return nil, err
+	}
+	obj := FirstObject(r)
+	if obj == nil {
+		return nil, fmt.Errorf("export job %q %w", job, ErrNotFound)
+	}
+	return &ExportJob{
+		ID:        num(obj, "id"),
+		Name:      str(obj, "name", "JobName"),
+		StartTime: num(obj, "start_time"),
+		Status:    str(obj, "status", "Status"),
+		Output:    str(obj, "output", "status_text", "status_long_text"),
+	}, nil
+}
+
+// ParseVerifyOutput extracts the "Error:" and "Warning:" lines Nagios prints
+// when verifying a configuration (nagios -v).
+func ParseVerifyOutput(out string) (errs, warnings []string) {
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		lower := strings.ToLower(line)
+		switch {
+		case strings.HasPrefix(lower, "error:"):
+			errs = append(errs, strings.TrimSpace(line[len("error:"):]))
+		case strings.HasPrefix(lower, "warning:"):
+			warnings = append(warnings, strings.TrimSpace(line[len("warning:"):]))
+		}
+	}
+	return errs, warnings
+}
@@ -0,0 +1,370 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EONAPI returns Lilac rows from the get* endpoints and livestatus rows from
+// listNagiosObjects, which spell the same fields differently. The decoders
+// below accept both spellings. List and map fields are nil when the row
+// does not carry them and empty when it reports no elements.
+
+// Host is the typed view of a host object.
+type Host struct {
+	Name          string
+	Address       string
+	Alias         string
+	Templates     []string
+	HostGroups    []string
+	Contacts      []string
+	ContactGroups []string
+	Parents       []string
+
+	// Parameters holds the check and notification directives reported for
+	// the host, keyed by their Nagios name (see HostParameters).
+	Parameters map[string]string
+
+	// CustomVariables is keyed by the upper-case variable name with its
+	// leading underscore (_SNMP_COMMUNITY).
+	CustomVariables map[string]string
+}
+
+// DecodeHost maps a raw host object onto Host.
+func DecodeHost(obj map[string]interface{}) Host {
+	return Host{
+		Name:          str(obj, "name", "host_name", "hostName"),
+		Address:       str(obj, "address", "ip", "hostIp"),
+		Alias:         str(obj, "alias", "hostAlias"),
+		Templates:     strList(obj, "templates", "use", "host_templates"),
+		HostGroups:    strList(obj, "groups", "hostgroups", "host_groups"),
+		Contacts:      strList(obj, "contacts"),
+		ContactGroups: strList(obj, "contact_groups", "contactgroups"),
+		Parents:       strList(obj, "parents"),
+
+		Parameters:      hostParameters(obj),
+		CustomVariables: customVariables(strMap(obj, "custom_variables", "customArguments", "custom_arguments")),
+	}
+}
+
+// HostParameters are the host directives ModifyHostParameters accepts.
+var HostParameters = []string{
+	"max_check_attempts", "check_interval", "retry_interval",
+	"active_checks_enabled", "passive_checks_enabled",
+	"notification_interval", "notification_period", "flap_detection_enabled",
+}
+
+func hostParameters(obj map[string]interface{}) map[string]string {
+	out := map[string]string{}
+	for _, p := range HostParameters {
+		keys := []string{p}
+		if p == "passive_checks_enabled" {
+			// livestatus name
+			keys = append(keys, "accept_passive_checks")
+		}
+		if v := str(obj, keys...); v != "" {
+			out[p] = v
+		}
+	}
+	return out
+}
+
+// customVariables normalizes custom variable names: livestatus reports them
+// without the leading underscore.
+func customVariables(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		k = strings.ToUpper(k)
+		if !strings.HasPrefix(k, "_") {
+			k = "_" + k
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// Command is the typed view of a check command object.
+type Command struct {
+	Name        string
+	CommandLine string
+	Description string
+}
+
+// DecodeCommand maps a raw command object onto Command.
+func DecodeCommand(obj map[string]interface{}) Command {
+	return Command{
+		Name:        str(obj, "name", "command_name", "commandName"),
+		CommandLine: str(obj, "line", "command_line", "commandLine"),
+		Description: str(obj, "description", "command_desc", "commandDescription"),
+	}
+}
+
+// Contact is the typed view of a contact object.
+type Contact struct {
+	Name          string
+	Alias         string
+	Mail          string
+	Pager         string
+	ContactGroups []string
+
+	HostNotificationPeriod      string
+	ServiceNotificationPeriod   string
+	HostNotificationOptions     []string
+	ServiceNotificationOptions  []string
+	HostNotificationCommands    []string
+	ServiceNotificationCommands []string
+}
+
+// DecodeContact maps a raw contact object onto Contact.
+func DecodeContact(obj map[string]interface{}) Contact {
+	return Contact{
+		Name:          str(obj, "name", "contact_name", "contactName"),
+		Alias:         str(obj, "alias", "contactAlias"),
+		Mail:          str(obj, "email", "mail", "contactMail"),
+		Pager:         str(obj, "pager", "contactPager"),
+		ContactGroups: strList(obj, "contactgroups", "contact_groups", "groups"),
+
+		HostNotificationPeriod:      str(obj, "host_notification_period", "hostNotificationPeriod"),
+		ServiceNotificationPeriod:   str(obj, "service_notification_period", "serviceNotificationPeriod"),
+		HostNotificationOptions:     strList(obj, "host_notification_options", "hostNotificationOptions"),
+		ServiceNotificationOptions:  strList(obj, "service_notification_options", "serviceNotificationOptions"),
+		HostNotificationCommands:    strList(obj, "host_notification_commands", "hostNotificationCommands"),
+		ServiceNotificationCommands: strList(obj, "service_notification_commands", "serviceNotificationCommands"),
+	}
+}
+
+// ContactGroup is the typed view of a contact group object.
+type ContactGroup struct {
+	Name        string
+	Description string
+	Members     []string
+}
+
+// DecodeContactGroup maps a raw contact group object onto ContactGroup.
+func DecodeContactGroup(obj map[string]interface{}) ContactGroup {
+	return ContactGroup{
+		Name:        str(obj, "name", "contactgroup_name", "contactGroupName"),
+		Description: str(obj, "alias", "description"),
+		Members:     strList(obj, "members"),
+	}
+}
+
+// Timeperiod is the typed view of a timeperiod object. Weekdays maps day
+// names and Exceptions maps Nagios date exceptions to time ranges.
+type Timeperiod struct {
+	Name       string
+	Alias      string
+	Weekdays   map[string]string
+	Exceptions map[string]string
+	Exclude    []string
+}
+
+// DecodeTimeperiod maps a raw timeperiod object onto Timeperiod.
+func DecodeTimeperiod(obj map[string]interface{}) Timeperiod {
+	return Timeperiod{
+		Name:       str(obj, "name", "timeperiod_name", "timeperiodName"),
+		Alias:      str(obj, "alias"),
+		Weekdays:   strMap(obj, "weekdays"),
+		Exceptions: strMap(obj, "exceptions"),
+		Exclude:    strList(obj, "exclude", "exclusions"),
+	}
+}
+
+// User is the typed view of an EON web user. Passwords are never returned.
+type User struct {
+	Name          string
+	Description   string
+	Mail          string
+	Group         string
+	AuthType      string // "local" or "ldap", "" when not returned
+	LDAPDN        string
+	LinkedContact *bool // nil when not returned
+}
+
+// DecodeUser maps a raw EON user row onto User.
+func DecodeUser(obj map[string]interface{}) User {
+	u := User{
+		Name:        str(obj, "user_name", "name", "userName"),
+		Description: str(obj, "user_descr", "description", "userDescr"),
+		Mail:        str(obj, "user_email", "email", "userMail"),
+		Group:       str(obj, "group_name", "group", "userGroup"),
+		LDAPDN:      str(obj, "ldap_dn", "ldapDn"),
+	}
+	switch str(obj, "user_type", "ldap") {
+	case "":
+	case "0", "false":
+		u.AuthType = "local"
+	default:
+		u.AuthType = "ldap"
+	}
+	if s := str(obj, "nagios_contact", "linked_contact"); s != "" {
+		linked := s != "0" && s != "false"
+		u.LinkedContact = &linked
+	}
+	return u
+}
+
+// UserGroup is the typed view of an EON web user group.
+type UserGroup struct {
+	Name        string
+	Description string
+	LDAPGroup   string
+}
+
+// DecodeUserGroup maps a raw EON group row onto UserGroup.
+func DecodeUserGroup(obj map[string]interface{}) UserGroup {
+	return UserGroup{
+		Name:        str(obj, "group_name", "name", "groupName"),
+		Description: str(obj, "group_descr", "description", "groupDescr"),
+		LDAPGroup:   str(obj, "group_dn", "ldap_group", "ldapGroup"),
+	}
+}
+
+// State is a livestatus host or service state row.
+type State struct {
+	HostName     string
+	Description  string // empty for host states
+	State        int64
+	Output       string
+	LastCheck    int64 // unix seconds
+	Acknowledged bool
+	InDowntime   bool
+}
+
+// DecodeState maps a raw livestatus state row onto State.
+func DecodeState(obj map[string]interface{}) State {
+	return State{
+		HostName:     str(obj, "host_name", "name"),
+		Description:  str(obj, "description", "service_description"),
+		State:        num(obj, "state"),
+		Output:       str(obj, "plugin_output", "output"),
+		LastCheck:    num(obj, "last_check"),
+		Acknowledged: num(obj, "acknowledged") != 0,
+		InDowntime:   num(obj, "scheduled_downtime_depth") > 0,
+	}
+}
+
+// Downtime is a scheduled host or service downtime. Times are unix seconds,
+// Duration is in seconds and only matters for flexible downtimes.
+type Downtime struct {
+	ID                 int64
+	HostName           string
+	ServiceDescription string // empty for host downtimes
+	StartTime          int64
+	EndTime            int64
+	Fixed              bool
+	Duration           int64
+	Author             string
+	Comment            string
+}
+
+// DecodeDowntime maps a raw livestatus downtime row onto Downtime.
+func DecodeDowntime(obj map[string]interface{}) Downtime {
+	return Downtime{
+		ID:                 num(obj, "id"),
+		HostName:           str(obj, "host_name"),
+		ServiceDescription: str(obj, "service_description"),
+		StartTime:          num(obj, "start_time"),
+		EndTime:            num(obj, "end_time"),
+		Fixed:              num(obj, "fixed") != 0,
+		Duration:           num(obj, "duration"),
+		Author:             str(obj, "author"),
+		Comment:            str(obj, "comment"),
+	}
+}
+
+// FirstObject returns the first object of a response, or nil.
+func FirstObject(r *APIResponse) map[string]interface{} {
+	objs := ResultObjects(r)
+	if len(objs) == 0 {
+		return nil
+	}
+	return objs[0]
+}
+
+func str(obj map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		switch v := obj[k].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64, bool:
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+func num(obj map[string]interface{}, keys ...string) int64 {
+	for _, k := range keys {
+		switch v := obj[k].(type) {
+		case float64:
+			return int64(v)
+		case bool:
+			if v {
+				return 1
+			}
+			return 0
+		case string:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// strMap reads a JSON object of strings; it returns nil when none of the
+// keys is present.
+func strMap(obj map[string]interface{}, keys ...string) map[string]string {
+	for _, k := range keys {
+		if m, ok := obj[k].(map[string]interface{}); ok {
+			out := make(map[string]string, len(m))
+			for mk, v := range m {
+				out[mk] = fmt.Sprint(v)
+			}
+			return out
+		}
+	}
+	return nil
+}
+
+// strList reads a list that may come back as a JSON array or as a Nagios
+// comma-separated string. It returns nil when none of the keys is present
+// and an empty list when the field is present but empty.
+func strList(obj map[string]interface{}, keys ...string) []string {
+	var found bool
+	for _, k := range keys {
+		switch v := obj[k].(type) {
+		case []interface{}:
+			out := make([]string, 0, len(v))
+			for _, e := range v {
+				if s := fmt.Sprint(e); s != "" {
+					out = append(out, s)
+				}
+			}
+			return out
+		case string:
+			found = true
+			if v == "" {
+				continue
+			}
+			out := []string{}
+			for _, s := range strings.Split(v, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					out = append(out, s)
+				}
+			}
+			return out
+		}
+	}
+	if found {
+		return []string{}
+	}
+	return nil
+}
@@ -0,0 +1,1275 @@
+// Package eontest provides an in-memory stand-in for the EONAPI so the
+// client and the provider can be exercised without an EON appliance.
+package eontest
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+)
+
+// Object kinds, named after the livestatus tables listNagiosObjects serves.
+const (
+	Hosts         = "hosts"
+	Commands      = "commands"
+	Contacts      = "contacts"
+	ContactGroups = "contactgroups"
+	Timeperiods   = "timeperiods"
+	Users         = "users"
+	UserGroups    = "usergroups"
+	Downtimes     = "downtimes"
+)
+
+// Default credentials accepted by a Server.
+const (
+	Username = "admin"
+	APIKey   = "eontest-api-key"
+)
+
+// Object is a stored EON object. Field names follow the livestatus columns
+// (name, address, alias, line, email, members, ...).
+type Object map[string]interface{}
+
+// Server is an httptest.Server emulating the EONAPI endpoints used by the
+// client. Objects live in memory and can be inspected or modified behind
+// the client's back to simulate drift.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	objects    map[string]map[string]Object
+	calls      map[string]int
+	exportJobs map[string]Object
+
+	// runtime holds livestatus state rows keyed by runtimeKey. Host rows
+	// default to UP; service rows only exist once SetState created them.
+	runtime map[string]Object
+
+	lastDowntimeID int64
+
+	lastExportID int64
+	queuedJobs   map[string]*queuedJob
+
+	// failures is how many upcoming calls of an endpoint Fail makes
+	// answer 503.
+	failures map[string]int
+
+	// omitted lists the fields Omit drops from the responses of an
+	// endpoint.
+	omitted map[string][]string
+
+	// ExportOutput is the Nagios verify output of every export job;
+	// ExportFailed marks the jobs as failed. ExportQueued is how many
+	// getExportJob calls keep reporting the previous run of a job name
+	// before a new run is picked up.
+	ExportOutput string
+	ExportFailed bool
+	ExportQueued int
+}
+
+type queuedJob struct {
+	job   Object
+	polls int
+}
+
+type handlerFunc func(s *Server, body map[string]interface{}) (int, interface{})
+
+var handlers = map[string]handlerFunc{
+	"createHost":               (*Server).createHost,
+	"getHost":                  getter(Hosts, "hostName"),
+	"deleteHost":               (*Server).deleteHost,
+	"addHostTemplateToHost":    (*Server).addHostTemplateToHost,
+	"deleteHostTemplateToHost": (*Server).deleteHostTemplateToHost,
+
+	"addContactToHost":         hostLink("contacts", "contactName", Contacts, true),
+	"deleteContactToHost":      hostLink("contacts", "contactName", Contacts, false),
+	"addContactGroupToHost":    hostLink("contact_groups", "contactGroupName", ContactGroups, true),
+	"deleteContactGroupToHost": hostLink("contact_groups", "contactGroupName", ContactGroups, false),
+
+	"addParentToHost":    (*Server).addParentToHost,
+	"deleteParentToHost": hostLink("parents", "parentName", Hosts, false),
+
+	"modifyHostParameters": (*Server).modifyHostParameters,
+
+	"addCustomArgumentsToHost":    customArguments("add"),
+	"modifyCustomArgumentsToHost": customArguments("modify"),
+	"deleteCustomArgumentsToHost": customArguments("delete"),
+
+	"addCommand":    (*Server).addCommand,
+	"getCommand":    getter(Commands, "commandName"),
+	"modifyCommand": (*Server).modifyCommand,
+	"deleteCommand": deleter(Commands, "commandName"),
+
+	"createContact": (*Server).createContact,
+	"getContact":    (*Server).getContact,
+	"modifyContact": (*Server).modifyContact,
+	"deleteContact": (*Server).deleteContact,
+
+	"addContactGroupToContact":    contactGroupLink(true),
+	"deleteContactGroupToContact": contactGroupLink(false),
+
+	"createContactGroup": (*Server).createContactGroup,
+	"getContactGroup":    getter(ContactGroups, "contactGroupName"),
+	"modifyContactGroup": (*Server).modifyContactGroup,
+	"deleteContactGroup": deleter(ContactGroups, "contactGroupName"),
+
+	"createTimeperiod": (*Server).createTimeperiod,
+	"getTimeperiod":    getter(Timeperiods, "timeperiodName"),
+	"modifyTimeperiod": (*Server).modifyTimeperiod,
+	"deleteTimeperiod": (*Server).deleteTimeperiod,
+
+	"createUser": (*Server).createUser,
+	"getUser":    (*Server).getUser,
+	"modifyUser": (*Server).modifyUser,
+	"deleteUser": (*Server).deleteUser,
+
+	"createUserGroup": (*Server).createUserGroup,
+	"getUserGroup":    getter(UserGroups, "groupName"),
+	"modifyUserGroup": (*Server).modifyUserGroup,
+	"deleteUserGroup": (*Server).deleteUserGroup,
+
+	"exportConfiguration": (*Server).exportConfiguration,
+	"getExportJob":        (*Server).getExportJob,
+
+	"createHostDowntime":    createDowntime(false),
+	"createServiceDowntime": createDowntime(true),
+	"deleteHostDowntime":    deleteDowntime(false),
+	"deleteServiceDowntime": deleteDowntime(true),
+
+	"acknowledgeHostProblem":       acknowledge(false),
+	"acknowledgeServiceProblem":    acknowledge(true),
+	"removeHostAcknowledgement":    removeAcknowledgement(false),
+	"removeServiceAcknowledgement": removeAcknowledgement(true),
+
+	"listNagiosObjects": (*Server).listNagiosObjects,
+	"listNagiosStates":  (*Server).listNagiosStates,
+}
+
+// NewServer starts a Server. Callers must Close it.
+func NewServer() *Server {
+	s := &Server{
+		objects:      map[string]map[string]Object{},
+		calls:        map[string]int{},
+		exportJobs:   map[string]Object{},
+		queuedJobs:   map[string]*queuedJob{},
+		failures:     map[string]int{},
+		omitted:      map[string][]string{},
+		runtime:      map[string]Object{},
+		ExportOutput: "Total Warnings: 0\nTotal Errors:   0\n",
+	}
+	for _, k := range []string{Hosts, Commands, Contacts, ContactGroups, Timeperiods, Users, UserGroups, Downtimes} {
+		s.objects[k] = map[string]Object{}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a client authenticated against the server.
+func (s *Server) Client() *client.Client {
+	return client.NewClient(s.URL, Username, APIKey, false)
+}
+
+// Get returns a copy of an object, or nil if it does not exist.
+func (s *Server) Get(kind, name string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.objects[kind][name]
+	if !ok {
+		return nil
+	}
+	return copyObject(o)
+}
+
+// Put creates or replaces an object.
+func (s *Server) Put(kind, name string, o Object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o = copyObject(o)
+	o["name"] = name
+	if s.objects[kind] == nil {
+		s.objects[kind] = map[string]Object{}
+	}
+	s.objects[kind][name] = o
+}
+
+// Set changes a single field of an existing object.
+func (s *Server) Set(kind, name, field string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if o, ok := s.objects[kind][name]; ok {
+		o[field] = value
+	}
+}
+
+// Delete removes an object.
+func (s *Server) Delete(kind, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects[kind], name)
+}
+
+// Calls returns how many times an endpoint was called.
+func (s *Server) Calls(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[endpoint]
+}
+
+// SetState sets the livestatus state and plugin output of a host, or of one
+// of its services when service is set, as if Nagios had just checked it.
+// The host does not need to exist yet. Like Nagios, a recovery clears the
+// acknowledgement, and so does any state change unless it is sticky.
+func (s *Server) SetState(host, service string, state int, output string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.runtimeRow(host, service)
+	if state == 0 || (state != r["state"] && r["acknowledgement_type"] != 2) {
+		r["acknowledged"], r["acknowledgement_type"] = 0, 0
+	}
+	r["state"] = state
+	r["plugin_output"] = output
+	r["last_check"] = time.Now().Unix()
+}
+
+// ExpireDowntimes drops every downtime whose end time has passed, as Nagios
+// does when the window closes.
+func (s *Server) ExpireDowntimes() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().Unix()
+	for id, d := range s.objects[Downtimes] {
+		if d["end_time"].(int64) <= now {
+			delete(s.objects[Downtimes], id)
+		}
+	}
+}
+
+// ─── HTTP plumbing ────────────────────────────────────────────────
+
+// Fail makes the next n calls of endpoint answer 503 Service Unavailable.
+func (s *Server) Fail(endpoint string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[endpoint] = n
+}
+
+// Omit makes the responses of endpoint leave out fields, as EON does for
+// some objects. Calling it without fields restores the full responses.
+func (s *Server) Omit(endpoint string, fields ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.omitted[endpoint] = fields
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("username") != Username || q.Get("apiKey") != APIKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	endpoint := strings.TrimPrefix(r.URL.Path, "/")
+
+	if endpoint == "getAuthenticationStatus" {
+		s.count(endpoint)
+		writeJSON(w, http.StatusOK, client.APIResponse{
+			APIVersion: "2.4.2", HTTPCode: "200 OK", Status: "Authorized",
+		})
+		return
+	}
+
+	h, ok := handlers[endpoint]
+	if !ok || r.Method != http.MethodPost {
+		writeError(w, http.StatusNotFound, "unknown endpoint "+endpoint)
+		return
+	}
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[endpoint]++
+	var (
+		code   int
+		result interface{}
+	)
+	if s.failures[endpoint] > 0 {
+		s.failures[endpoint]--
+		code, result = http.StatusServiceUnavailable, endpoint+" is unavailable"
+	} else {
+		code, result = h(s, body)
+	}
+	if objs, ok := result.([]interface{}); ok && len(s.omitted[endpoint]) > 0 {
+		for _, o := range objs {
+			if o, ok := o.(Object); ok {
+				for _, f := range s.omitted[endpoint] {
+					delete(o, f)
+				}
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if code >= 400 {
+		writeError(w, code, fmt.Sprint(result))
+		return
+	}
+	writeJSON(w, code, client.APIResponse{APIVersion: "2.4.2", HTTPCode: httpCode(code), Result: result})
+}
+
+func (s *Server) count(endpoint string) {
+	s.mu.Lock()
+	s.calls[endpoint]++
+	s.mu.Unlock()
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError mirrors the EONAPI error shape: the HTTP status plus a JSON
+// body carrying http_code and a message in result.
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, client.APIResponse{APIVersion: "2.4.2", HTTPCode: httpCode(code), Result: msg})
+}
+
+func httpCode(code int) string {
+	return fmt.Sprintf("%d %s", code, http.StatusText(code))
+}
+
+func copyObject(o Object) Object {
+	out := Object{}
+	for k, v := range o {
+		switch t := v.(type) {
+		case []string:
+			v = append(make([]string, 0, len(t)), t...)
+		case map[string]string:
+			m := make(map[string]string, len(t))
+			for mk, mv := range t {
+				m[mk] = mv
+			}
+			v = m
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func strField(body map[string]interface{}, key string) string {
+	s, _ := body[key].(string)
+	return s
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, e := range list {
+		if e == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
+
+func removeString(list []string, v string) []string {
+	out := list[:0:0]
+	for _, e := range list {
+		if e != v {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func intField(body map[string]interface{}, key string) int64 {
+	f, _ := body[key].(float64)
+	return int64(f)
+}
+
+func stringsOf(o Object, field string) []string {
+	l, _ := o[field].([]string)
+	return l
+}
+
+// ─── Generic handlers ─────────────────────────────────────────────
+
+func getter(kind, param string) handlerFunc {
+	return func(s *Server, body map[string]interface{}) (int, interface{}) {
+		name := strField(body, param)
+		o, ok := s.objects[kind][name]
+		if !ok {
+			return http.StatusNotFound, fmt.Sprintf("%s %q not found", kind, name)
+		}
+		return http.StatusOK, []interface{}{copyObject(o)}
+	}
+}
+
+func deleter(kind, param string) handlerFunc {
+	return func(s *Server, body map[string]interface{}) (int, interface{}) {
+		name := strField(body, param)
+		if _, ok := s.objects[kind][name]; !ok {
+			return http.StatusNotFound, fmt.Sprintf("%s %q not found", kind, name)
+		}
+		delete(s.objects[kind], name)
+		return http.StatusOK, fmt.Sprintf("%s %q deleted", kind, name)
+	}
+}
+
+func (s *Server) create(kind, name string, o Object) (int, interface{}) {
+	if name == "" {
+		return http.StatusBadRequest, "missing object name"
+	}
+	if _, ok := s.objects[kind][name]; ok {
+		return http.StatusConflict, fmt.Sprintf("%s %q already exists", kind, name)
+	}
+	o["name"] = name
+	s.objects[kind][name] = o
+	return http.StatusOK, fmt.Sprintf("%s %q created", kind, name)
+}
+
+// rename moves an object to newName when set, keeping it otherwise.
+func (s *Server) rename(kind, name, newName string) (Object, int, interface{}) {
+	o, ok := s.objects[kind][name]
+	if !ok {
+		return nil, http.StatusNotFound, fmt.Sprintf("%s %q not found", kind, name)
+	}
+	if newName != "" && newName != name {
+		if _, taken := s.objects[kind][newName]; taken {
+			return nil, http.StatusConflict, fmt.Sprintf("%s %q already exists", kind, newName)
+		}
+		delete(s.objects[kind], name)
+		o["name"] = newName
+		s.objects[kind][newName] = o
+	}
+	return o, http.StatusOK, nil
+}
+
+// ─── Hosts ────────────────────────────────────────────────────────
+
+func (s *Server) createHost(body map[string]interface{}) (int, interface{}) {
+	o := Object{
+		"address":        strField(body, "hostIp"),
+		"alias":          strField(body, "hostAlias"),
+		"templates":      []string{},
+		"contacts":       []string{},
+		"contact_groups": []string{},
+		"groups":         []string{},
+		"parents":        []string{},
+
+		"custom_variables": map[string]string{},
+	}
+	if t := strField(body, "templateHostName"); t != "" {
+		o["templates"] = []string{t}
+	}
+	if c := strField(body, "contactName"); c != "" {
+		o["contacts"] = []string{c}
+	}
+	if g := strField(body, "contactGroupName"); g != "" {
+		o["contact_groups"] = []string{g}
+	}
+	return s.create(Hosts, strField(body, "hostName"), o)
+}
+
+func (s *Server) addHostTemplateToHost(body map[string]interface{}) (int, interface{}) {
+	name := strField(body, "hostName")
+	o, ok := s.objects[Hosts][name]
+	if !ok {
+		return http.StatusNotFound, fmt.Sprintf("hosts %q not found", name)
+	}
+	o["templates"] = appendUnique(stringsOf(o, "templates"), strField(body, "templateHostName"))
+	return http.StatusOK, "template added"
+}
+
+func (s *Server) deleteHostTemplateToHost(body map[string]interface{}) (int, interface{}) {
+	name, tpl := strField(body, "hostName"), strField(body, "templateHostName")
+	o, ok := s.objects[Hosts][name]
+	if !ok {
+		return http.StatusNotFound, fmt.Sprintf("hosts %q not found", name)
+	}
+	if !containsString(stringsOf(o, "templates"), tpl) {
+		return http.StatusNotFound, fmt.Sprintf("template %q is not linked to host %q", tpl, name)
+	}
+	o["templates"] = removeString(stringsOf(o, "templates"), tpl)
+	return http.StatusOK, "template removed"
+}
+
+// hostLink adds (or removes) the object named by body[param] to (from) the
+// host's field list. Linked objects must exist; removing an unlinked one
+// fails like EONAPI does.
+func hostLink(field, param, kind string, add bool) handlerFunc {
+	return func(s *Server, body map[string]interface{}) (int, interface{}) {
+		name, v := strField(body, "hostName"), strField(body, param)
+		o, ok := s.objects[Hosts][name]
+		if !ok {
+			return http.StatusNotFound, fmt.Sprintf("hosts %q not found", name)
+		}
+		list := stringsOf(o, field)
+		switch {
+		case add && s.objects[kind][v] == nil:
+			return http.StatusNotFound, fmt.Sprintf("%s %q not found", kind, v)
+		case add:
+			o[field] = appendUnique(list, v)
+		case !containsString(list, v):
+			return http.StatusNotFound, fmt.Sprintf("%q is not linked to host %q", v, name)
+		default:
+			o[field] = removeString(list, v)
+		}
+		return http.StatusOK, fmt.Sprintf("%s updated", field)
+	}
+}
+
+// deleteHost deletes a host and, like EON, the links of other hosts to it
+// as a parent.
+func (s *Server) deleteHost(body map[string]interface{}) (int, interface{}) {
+	code, result := deleter(Hosts, "hostName")(s, body)
+	if code == http.StatusOK {
+		name := strField(body, "hostName")
+		for _, o := range s.objects[Hosts] {
+			if list := stringsOf(o, "parents"); containsString(list, name) {
+				o["parents"] = removeString(list, name)
+			}
+		}
+	}
+	return code, result
+}
+
+// addParentToHost links a parent host. Like EONAPI it does not look for
+// cycles: Nagios only reports them when the configuration is exported.
+func (s *Server) addParentToHost(body map[string]interface{}) (int, interface{}) {
+	if strField(body, "parentName") == strField(body, "hostName") {
+		return http.StatusBadRequest, fmt.Sprintf("host %q cannot be its own parent", strField(body, "hostName"))
+	}
+	return hostLink("parents", "parentName", Hosts, true)(s, body)
+}
+
+// modifyHostParameters sets host directives; an empty value removes the
+// directive from the host.
+func (s *Server) modifyHostParameters(body map[string]interface{}) (int, interface{}) {
+	name := strField(body, "hostName")
+	o, ok := s.objects[Hosts][name]
+	if !ok {
+		return http.StatusNotFound, fmt.Sprintf("hosts %q not found", name)
+	}
+	params, _ := body["parameters"].(map[string]interface{})
+	for k := range params {
+		if !containsString(client.HostParameters, k) {
+			return http.StatusBadRequest, fmt.Sprintf("unknown host parameter %q", k)
+		}
+	}
+	for k, v := range params {
+		if v == "" {
+			delete(o, k)
+		} else {
+			o[k] = fmt.Sprint(v)
+		}
+	}
+	return http.StatusOK, "host parameters updated"
+}
+
+// customArguments adds, modifies or deletes host custom variables. Like
+// EONAPI, adding an existing variable or changing or deleting a missing one
+// fails and leaves the host unchanged.
+func customArguments(op string) handlerFunc {
+	return func(s *Server, body map[string]interface{}) (int, interface{}) {
+		name := strField(body, "hostName")
+		o, ok := s.objects[Hosts][name]
+		if !ok {
+			return http.StatusNotFound, fmt.Sprintf("hosts %q not found", name)
+		}
+		vars, _ := o["custom_variables"].(map[string]string)
+		if vars == nil {
+			vars = map[string]string{}
+		}
+		args := map[string]string{}
+		switch v := body["customArguments"].(type) {
+		case map[string]interface{}:
+			for k, val := range v {
+				args[k] = fmt.Sprint(val)
+			}
+		case []interface{}:
+			for _, k := range v {
+				args[fmt.Sprint(k)] = ""
+			}
+		}
+		for k := range args {
+			_, exists := vars[k]
+			switch {
+			case op == "add" && exists:
+				return http.StatusConflict, fmt.Sprintf("custom argument %q already exists on host %q", k, name)
+			case op != "add" && !exists:
+				return http.StatusNotFound, fmt.Sprintf("custom argument %q not found on host %q", k, name)
+			}
+		}
+		for k, v := range args {
+			if op == "delete" {
+				delete(vars, k)
+			} else {
+				vars[k] = v
+			}
+		}
+		o["custom_variables"] = vars
+		return http.StatusOK, "custom arguments updated"
+	}
+}
+
+// ─── Commands ─────────────────────────────────────────────────────
+
+func (s *Server) addCommand(body map[string]interface{}) (int, interface{}) {
+	return s.create(Commands, strField(body, "commandName"), Object{
+		"line":        strField(body, "commandLine"),
+		"description": strField(body, "commandDescription"),
+	})
+}
+
+func (s *Server) modifyCommand(body map[string]interface{}) (int, interface{}) {
+	o, code, msg := s.rename(Commands, strField(body, "commandName"), strField(body, "newCommandName"))
+	if o == nil {
+		return code, msg
+	}
+	o["line"] = strField(body, "commandLine")
+	o["description"] = strField(body, "commandDescription")
+	return http.StatusOK, "command modified"
+}
+
+// ─── Contacts ─────────────────────────────────────────────────────
+
+// contactFields maps contact body parameters onto stored fields. Option and
+// command lists are stored as sent, comma separated.
+var contactFields = map[string]string{
+	"contactAlias": "alias", "contactMail": "email", "contactPager": "pager",
+	"hostNotificationPeriod":      "host_notification_period",
+	"serviceNotificationPeriod":   "service_notification_period",
+	"hostNotificationOptions":     "host_notification_options",
+	"serviceNotificationOptions":  "service_notification_options",
+	"hostNotificationCommands":    "host_notification_commands",
+	"serviceNotificationCommands": "service_notification_commands",
+}
+
+func (s *Server) createContact(body map[string]interface{}) (int, interface{}) {
+	name := strField(body, "contactName")
+	group := strField(body, "contactGroup")
+	if group != "" {
+		if _, ok := s.objects[ContactGroups][group]; !ok {
+			return http.StatusNotFound, fmt.Sprintf("contactgroups %q not found", group)
+		}
+	}
+	o := Object{}
+	for param, field := range contactFields {
+		o[field] = strField(body, param)
+	}
+	code, msg := s.create(Contacts, name, o)
+	if code == http.StatusOK && group != "" {
+		g := s.objects[ContactGroups][group]
+		g["members"] = appendUnique(stringsOf(g, "members"), name)
+	}
+	return code, msg
+}
+
+// Group membership is stored on the group's members; contact reads report
+// it as contactgroups like Lilac does.
+func (s *Server) getContact(body map[string]interface{}) (int, interface{}) {
+	code, result := getter(Contacts, "contactName")(s, body)
+	if code == http.StatusOK {
+		o := result.([]interface{})[0].(Object)
+		o["contactgroups"] = s.contactGroupsOf(strField(body, "contactName"))
+	}
+	return code, result
+}
+
+func (s *Server) contactGroupsOf(contact string) []string {
+	groups := []string{}
+	for name, g := range s.objects[ContactGroups] {
+		if containsString(stringsOf(g, "members"), contact) {
+			groups = append(groups, name)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// contactGroupLink adds (or removes) a contact to (from) a contact group.
+func contactGroupLink(add bool) handlerFunc {
+	return func(s *Server, body map[string]interface{}) (int, interface{}) {
+		name, group := strField(body, "contactName"), strField(body, "contactGroupName")
+		if _, ok := s.objects[Contacts][name]; !ok {
+			return http.StatusNotFound, fmt.Sprintf("contacts %q not found", name)
+		}
+		g, ok := s.objects[ContactGroups][group]
+		if !ok {
+			return http.StatusNotFound, fmt.Sprintf("contactgroups %q not found", group)
+		}
+		members := stringsOf(g, "members")
+		switch {
+		case add:
+			g["members"] = appendUnique(members, name)
+		case !containsString(members, name):
+			return http.StatusNotFound, fmt.Sprintf("contact %q is not a member of %q", name, group)
+		default:
+			g["members"] = removeString(members, name)
+		}
+		return http.StatusOK, "contact groups updated"
+	}
+}
+
+func (s *Server) modifyContact(body map[string]interface{}) (int, interface{}) {
+	name := strField(body, "contactName")
+	newName := strField(body, "newContactName")
+	o, code, msg := s.rename(Contacts, name, newName)
+	if o == nil {
+		return code, msg
+	}
+	if newName != "" && newName != name {
+		for _, g := range s.objects[ContactGroups] {
+			if m := stringsOf(g, "members"); containsString(m, name) {
+				g["members"] = appendUnique(removeString(m, name), newName)
+			}
+		}
+	}
+	for param, field := range contactFields {
+		if _, ok := body[param]; ok {
+			o[field] = strField(body, param)
+		}
+	}
+	return http.StatusOK, "contact modified"
+}
+
+func (s *Server) deleteContact(body map[string]interface{}) (int, interface{}) {
+	name := strField(body, "contactName")
+	code, msg := deleter(Contacts, "contactName")(s, body)
+	if code == http.StatusOK {
+		for _, g := range s.objects[ContactGroups] {
+			g["members"] = removeString(stringsOf(g, "members"), name)
+		}
+	}
+	return code, msg
+}
+
+// ─── Contact groups ───────────────────────────────────────────────
+
+func (s *Server) createContactGroup(body map[string]interface{}) (int, interface{}) {
+	return s.create(ContactGroups, strField(body, "contactGroupName"), Object{
+		"alias":   strField(body, "description"),
+		"members": []string{},
+	})
+}
+
+func (s *Server) modifyContactGroup(body map[string]interface{}) (int, interface{}) {
+	o, code, msg := s.rename(ContactGroups, strField(body, "contactGroupName"), strField(body, "newContactGroupName"))
+	if o == nil {
+		return code, msg
+	}
+	if _, ok := body["description"]; ok {
+		o["alias"] = strField(body, "description")
+	}
+	return http.StatusOK, "contact group modified"
+}
+
+// ─── Timeperiods ──────────────────────────────────────────────────
+
+func (s *Server) createTimeperiod(body map[string]interface{}) (int, interface{}) {
+	o := Object{}
+	setTimeperiodFields(o, body)
+	return s.create(Timeperiods, strField(body, "timeperiodName"), o)
+}
+
+func (s *Server) modifyTimeperiod(body map[string]interface{}) (int, interface{}) {
+	name, newName := strField(body, "timeperiodName"), strField(body, "newTimeperiodName")
+	o, code, msg := s.rename(Timeperiods, name, newName)
+	if o == nil {
+		return code, msg
+	}
+	if newName != "" && newName != name {
+		for _, t := range s.objects[Timeperiods] {
+			if ex := stringsOf(t, "exclude"); containsString(ex, name) {
+				t["exclude"] = appendUnique(removeString(ex, name), newName)
+			}
+		}
+	}
+	setTimeperiodFields(o, body)
+	return http.StatusOK, "timeperiod modified"
+}
+
+// deleteTimeperiod refuses to delete a timeperiod other timeperiods
+// exclude, like Lilac does.
+func (s *Server) deleteTimeperiod(body map[string]interface{}) (int, interface{}) {
+	name := strField(body, "timeperiodName")
+	for other, t := range s.objects[Timeperiods] {
+		if containsString(stringsOf(t, "exclude"), name) {
+			return http.StatusConflict, fmt.Sprintf("timeperiod %q is excluded by %q", name, other)
+		}
+	}
+	return deleter(Timeperiods, "timeperiodName")(s, body)
+}
+
+func setTimeperiodFields(o Object, body map[string]interface{}) {
+	o["alias"] = strField(body, "alias")
+	for _, k := range []string{"weekdays", "exceptions"} {
+		m := map[string]string{}
+		if v, ok := body[k].(map[string]interface{}); ok {
+			for day, ranges := range v {
+				m[day] = fmt.Sprint(ranges)
+			}
+		}
+		o[k] = m
+	}
+	exclude := []string{}
+	if v, ok := body["exclude"].([]interface{}); ok {
+		for _, e := range v {
+			exclude = append(exclude, fmt.Sprint(e))
+		}
+	}
+	o["exclude"] = exclude
+}
+
+// ─── EON users ────────────────────────────────────────────────────
+
+// Users are stored like EON's users table; the password is kept as an MD5
+// hash in user_passwd and never returned by getUser.
+
+func (s *Server) createUser(body map[string]interface{}) (int, interface{}) {
+	name, group := strField(body, "userName"), strField(body, "userGroup")
+	if _, ok := s.objects[UserGroups][group]; !ok {
+		return http.StatusNotFound, fmt.Sprintf("usergroups %q not found", group)
+	}
+	ldap := strField(body, "userType") == "ldap"
+	if !ldap && strField(body, "userPassword") == "" {
+		return http.StatusBadRequest, "local users need a password"
+	}
+	linked, _ := body["createContact"].(bool)
+	if linked {
+		if _, ok := s.objects[Contacts][name]; ok {
+			return http.StatusConflict, fmt.Sprintf("contacts %q already exists", name)
+		}
+		if strField(body, "userMail") == "" {
+			return http.StatusBadRequest, "a linked contact needs a mail address"
+		}
+	}
+	o := Object{"user_type": 0, "nagios_contact": 0}
+	if ldap {
+		o["user_type"] = 1
+	}
+	if linked {
+		o["nagios_contact"] = 1
+	}
+	setUserFields(o, body)
+	code, msg := s.create(Users, name, o)
+	if code == http.StatusOK && linked {
+		s.create(Contacts, name, Object{"alias": strField(body, "userDescr"), "email": strField(body, "userMail"), "pager": ""})
+	}
+	return code, msg
+}
+
+func (s *Server) getUser(body map[string]interface{}) (int, interface{}) {
+	code, result := getter(Users, "userName")(s, body)
+	if code == http.StatusOK {
+		delete(result.([]interface{})[0].(Object), "user_passwd")
+	}
+	return code, result
+}
+
+func (s *Server) modifyUser(body map[string]interface{}) (int, interface{}) {
+	if g, ok := body["userGroup"]; ok {
+		if _, found := s.objects[UserGroups][fmt.Sprint(g)]; !found {
+			return http.StatusNotFound, fmt.Sprintf("usergroups %q not found", g)
+		}
+	}
+	o, code, msg := s.rename(Users, strField(body, "userName"), strField(body, "newUserName"))
+	if o == nil {
+		return code, msg
+	}
+	if t, ok := body["userType"]; ok {
+		o["user_type"] = 0
+		if t == "ldap" {
+			o["user_type"] = 1
+		}
+	}
+	setUserFields(o, body)
+	return http.StatusOK, "user modified"
+}
+
+// deleteUser also deletes the user's linked contact.
+func (s *Server) deleteUser(body map[string]interface{}) (int, interface{}) {
+	name := strField(body, "userName")
+	o, ok := s.objects[Users][name]
+	if !ok {
+		return http.StatusNotFound, fmt.Sprintf("users %q not found", name)
+	}
+	delete(s.objects[Users], name)
+	if o["nagios_contact"] == 1 {
+		delete(s.objects[Contacts], name)
+		for _, g := range s.objects[ContactGroups] {
+			g["members"] = removeString(stringsOf(g, "members"), name)
+		}
+	}
+	return http.StatusOK, fmt.Sprintf("users %q deleted", name)
+}
+
+// setUserFields copies the user fields present in body onto o.
+func setUserFields(o Object, body map[string]interface{}) {
+	for param, field := range map[string]string{
+		"userDescr": "user_descr", "userMail": "user_email", "userGroup": "group_name", "ldapDn": "ldap_dn",
+	} {
+		if _, ok := body[param]; ok {
+			o[field] = strField(body, param)
+		}
+	}
+	if p := strField(body, "userPassword"); p != "" {
+		o["user_passwd"] = fmt.Sprintf("%x", md5.Sum([]byte(p)))
+	}
+}
+
+// ─── EON user groups ──────────────────────────────────────────────
+
+func (s *Server) createUserGroup(body map[string]interface{}) (int, interface{}) {
+	return s.create(UserGroups, strField(body, "groupName"), Object{
+		"group_descr": strField(body, "groupDescr"),
+		"group_dn":    strField(body, "ldapGroup"),
+	})
+}
+
+func (s *Server) modifyUserGroup(body map[string]interface{}) (int, interface{}) {
+	name, newName := strField(body, "groupName"), strField(body, "newGroupName")
+	o, code, msg := s.rename(UserGroups, name, newName)
+	if o == nil {
+		return code, msg
+	}
+	if newName != "" && newName != name {
+		for _, u := range s.objects[Users] {
+			if u["group_name"] == name {
+				u["group_name"] = newName
+			}
+		}
+	}
+	for param, field := range map[string]string{"groupDescr": "group_descr", "ldapGroup": "group_dn"} {
+		if _, ok := body[param]; ok {
+			o[field] = strField(body, param)
+		}
+	}
+	return http.StatusOK, "group modified"
+}
+
+// deleteUserGroup refuses to delete a group that still has users.
+func (s *Server) deleteUserGroup(body map[string]interface{}) (int, interface{}) {
+	name := strField(body, "groupName")
+	for user, u := range s.objects[Users] {
+		if u["group_name"] == name {
+			return http.StatusConflict, fmt.Sprintf("group %q still has user %q", name, user)
+		}
+	}
+	return deleter(UserGroups, "groupName")(s, body)
+}
+
+// ─── Export ───────────────────────────────────────────────────────
+
+func (s *Server) exportConfiguration(body map[string]interface{}) (int, interface{}) {
+	job := strField(body, "JobName")
+	if job == "" {
+		return http.StatusBadRequest, "missing JobName"
+	}
+	status := "Finished"
+	if s.ExportFailed {
+		status = "Failed"
+	}
+	s.lastExportID++
+	o := Object{
+		"id": s.lastExportID, "name": job, "start_time": time.Now().Unix(),
+		"status": status, "output": s.ExportOutput,
+	}
+	if s.ExportQueued > 0 {
+		s.queuedJobs[job] = &queuedJob{job: o, polls: s.ExportQueued}
+	} else {
+		s.exportJobs[job] = o
+	}
+	return http.StatusOK, fmt.Sprintf("export job %q started", job)
+}
+
+func (s *Server) getExportJob(body map[string]interface{}) (int, interface{}) {
+	job := strField(body, "JobName")
+	if q, ok := s.queuedJobs[job]; ok {
+		if q.polls == 0 {
+			s.exportJobs[job] = q.job
+			delete(s.queuedJobs, job)
+		} else {
+			q.polls--
+		}
+	}
+	o, ok := s.exportJobs[job]
+	if !ok {
+		return http.StatusNotFound, fmt.Sprintf("export job %q not found", job)
+	}
+	return http.StatusOK, []interface{}{copyObject(o)}
+}
+
+// ─── Downtimes ────────────────────────────────────────────────────
+
+// Downtimes are stored under Downtimes keyed by their id, with livestatus
+// column names, so listNagiosObjects serves them like any other table.
+
+func createDowntime(service bool) handlerFunc {
+	return func(s *Server, body map[string]interface{}) (int, interface{}) {
+		host, svc := strField(body, "hostName"), ""
+		if service {
+			svc = strField(body, "serviceName")
+		}
+		if code, msg := s.checkTarget(host, svc); code != http.StatusOK {
+			return code, msg
+		}
+		fixed := 0
+		if f, _ := body["fixed"].(bool); f {
+			fixed = 1
+		}
+		s.lastDowntimeID++
+		id := s.lastDowntimeID
+		s.objects[Downtimes][fmt.Sprint(id)] = Object{
+			"id":                  id,
+			"host_name":           host,
+			"service_description": svc,
+			"start_time":          intField(body, "startTime"),
+			"end_time":            intField(body, "endTime"),
+			"fixed":               fixed,
+			"duration":            intField(body, "duration"),
+			"author":              strField(body, "author"),
+			"comment":             strField(body, "comment"),
+		}
+		return http.StatusOK, fmt.Sprintf("downtime scheduled for %s", target(host, svc))
+	}
+}
+
+func deleteDowntime(service bool) handlerFunc {
+	return func(s *Server, body map[string]interface{}) (int, interface{}) {
+		id := fmt.Sprint(intField(body, "idDowntime"))
+		d, ok := s.objects[Downtimes][id]
+		if !ok || (d["service_description"] != "") != service {
+			return http.StatusNotFound, fmt.Sprintf("downtime %s not found", id)
+		}
+		delete(s.objects[Downtimes], id)
+		return http.StatusOK, fmt.Sprintf("downtime %s deleted", id)
+	}
+}
+
+// downtimeDepth counts the fixed downtimes of a host or service whose
+// window covers the current time.
+func (s *Server) downtimeDepth(host, service string) int {
+	now, depth := time.Now().Unix(), 0
+	for _, d := range s.objects[Downtimes] {
+		if d["host_name"] == host && d["service_description"] == service && d["fixed"] == 1 &&
+			d["start_time"].(int64) <= now && now < d["end_time"].(int64) {
+			depth++
+		}
+	}
+	return depth
+}
+
+// checkTarget reports whether livestatus knows the host, or the service
+// of the host when service is set.
+func (s *Server) checkTarget(host, service string) (int, string) {
+	if _, ok := s.objects[Hosts][host]; !ok {
+		return http.StatusNotFound, fmt.Sprintf("host %q not found", host)
+	}
+	if _, ok := s.runtime[runtimeKey(host, service)]; service != "" && !ok {
+		return http.StatusNotFound, fmt.Sprintf("service %q of host %q not found", service, host)
+	}
+	return http.StatusOK, ""
+}
+
+func target(host, service string) string {
+	if service == "" {
+		return fmt.Sprintf("host %q", host)
+	}
+	return fmt.Sprintf("service %q of host %q", service, host)
+}
+
+// ─── Acknowledgements ─────────────────────────────────────────────
+
+func acknowledge(service bool) handlerFunc {
+	return func(s *Server, body map[string]interface{}) (int, interface{}) {
+		host, svc := strField(body, "hostName"), ""
+		if service {
+			svc = strField(body, "serviceName")
+		}
+		if code, msg := s.checkTarget(host, svc); code != http.StatusOK {
+			return code, msg
+		}
+		r := s.runtimeRow(host, svc)
+		// Nagios ignores acknowledgements of objects that are not in a
+		// problem state.
+		if r["state"] != 0 {
+			r["acknowledged"], r["acknowledgement_type"] = 1, 1
+			if body["sticky"] == true {
+				r["acknowledgement_type"] = 2
+			}
+		}
+		return http.StatusOK, fmt.Sprintf("problem of %s acknowledged", target(host, svc))
+	}
+}
+
+func removeAcknowledgement(service bool) handlerFunc {
+	return func(s *Server, body map[string]interface{}) (int, interface{}) {
+		host, svc := strField(body, "hostName"), ""
+		if service {
+			svc = strField(body, "serviceName")
+		}
+		if code, msg := s.checkTarget(host, svc); code != http.StatusOK {
+			return code, msg
+		}
+		r := s.runtimeRow(host, svc)
+		r["acknowledged"], r["acknowledgement_type"] = 0, 0
+		return http.StatusOK, fmt.Sprintf("acknowledgement of %s removed", target(host, svc))
+	}
+}
+
+// runtimeRow returns the state row of a checked target, creating the
+// default UP row of a host on first use.
+func (s *Server) runtimeRow(host, service string) Object {
+	key := runtimeKey(host, service)
+	r, ok := s.runtime[key]
+	if !ok {
+		r = newRuntimeRow()
+		s.runtime[key] = r
+	}
+	return r
+}
+
+// ─── listNagiosObjects ────────────────────────────────────────────
+
+func (s *Server) listNagiosObjects(body map[string]interface{}) (int, interface{}) {
+	kind := strField(body, "object")
+	objs, ok := s.objects[kind]
+	if !ok {
+		return http.StatusOK, []interface{}{}
+	}
+	names := make([]string, 0, len(objs))
+	for n := range objs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	rows := make([]Object, 0, len(names))
+	for _, n := range names {
+		rows = append(rows, copyObject(objs[n]))
+	}
+	return filtered(rows, body)
+}
+
+// ─── listNagiosStates ─────────────────────────────────────────────
+
+func runtimeKey(host, service string) string {
+	return host + ";" + service
+}
+
+func newRuntimeRow() Object {
+	return Object{"state": 0, "plugin_output": "", "last_check": int64(0), "acknowledged": 0, "acknowledgement_type": 0}
+}
+
+func (s *Server) listNagiosStates(body map[string]interface{}) (int, interface{}) {
+	hosts := make([]string, 0, len(s.objects[Hosts]))
+	for n := range s.objects[Hosts] {
+		hosts = append(hosts, n)
+	}
+	sort.Strings(hosts)
+
+	var rows []Object
+	switch kind := strField(body, "object"); kind {
+	case "hosts":
+		for _, h := range hosts {
+			r, ok := s.runtime[runtimeKey(h, "")]
+			if !ok {
+				r = newRuntimeRow()
+			}
+			rows = append(rows, s.stateRow(h, "", r))
+		}
+	case "services":
+		keys := make([]string, 0, len(s.runtime))
+		for k := range s.runtime {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			host, service, _ := strings.Cut(k, ";")
+			if _, ok := s.objects[Hosts][host]; ok && service != "" {
+				rows = append(rows, s.stateRow(host, service, s.runtime[k]))
+			}
+		}
+	default:
+		return http.StatusBadRequest, fmt.Sprintf("unknown state table %q", kind)
+	}
+	return filtered(rows, body)
+}
+
+// stateRow builds a livestatus row: hosts are named by "name", services by
+// "host_name" and "description". Both carry the groups of their host.
+func (s *Server) stateRow(host, service string, r Object) Object {
+	row := copyObject(r)
+	if service == "" {
+		row["name"] = host
+	} else {
+		row["host_name"] = host
+		row["description"] = service
+	}
+	row["host_groups"] = append([]string{}, stringsOf(s.objects[Hosts][host], "groups")...)
+	row["scheduled_downtime_depth"] = s.downtimeDepth(host, service)
+	return row
+}
+
+// filtered applies the livestatus "Filter:" lines of a list request and
+// wraps the rows the way EONAPI does, keyed by livestatus backend name.
+func filtered(rows []Object, body map[string]interface{}) (int, interface{}) {
+	filters, _ := body["filters"].([]interface{})
+	out := make([]interface{}, 0, len(rows))
+rows:
+	for _, r := range rows {
+		for _, f := range filters {
+			ok, err := matchFilter(r, fmt.Sprint(f))
+			if err != nil {
+				return http.StatusBadRequest, err.Error()
+			}
+			if !ok {
+				continue rows
+			}
+		}
+		out = append(out, r)
+	}
+	return http.StatusOK, map[string]interface{}{"default": out}
+}
+
+// matchFilter evaluates "Filter: column op value" with the operators the
+// client uses: = on any column, >= (contains) on list columns.
+func matchFilter(o Object, filter string) (bool, error) {
+	parts := strings.SplitN(strings.TrimPrefix(filter, "Filter: "), " ", 3)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("invalid filter %q", filter)
+	}
+	column, op, value := parts[0], parts[1], parts[2]
+	switch op {
+	case "=":
+		if l, ok := o[column].([]string); ok {
+			return strings.Join(l, ",") == value, nil
+		}
+		if o[column] == nil {
+			return value == "", nil
+		}
+		return fmt.Sprint(o[column]) == value, nil
+	case ">=":
+		if l, ok := o[column].([]string); ok {
+			return containsString(l, value), nil
+		}
+	}
+	return false, fmt.Errorf("unsupported filter %q", filter)
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,424 @@
+package eontest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+)
+
+func TestAuth(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	if err := s.Client().CheckAuth(); err != nil {
+		t.Fatalf("CheckAuth: %s", err)
+	}
+	bad := client.NewClient(s.URL, Username, "wrong", false)
+	err := bad.CheckAuth()
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("CheckAuth with bad key: got %v, want 401 error", err)
+	}
+}
+
+func TestHostLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	_, err := c.CreateHost(map[string]interface{}{
+		"hostName": "web01", "hostIp": "10.0.0.1", "hostAlias": "Web",
+		"templateHostName": "GENERIC_HOST", "contactGroupName": "ops",
+	})
+	if err != nil {
+		t.Fatalf("CreateHost: %s", err)
+	}
+	if _, err := c.CreateHost(map[string]interface{}{"hostName": "web01"}); err == nil {
+		t.Fatal("CreateHost duplicate: expected error")
+	}
+	if _, err := c.AddHostTemplateToHost("LINUX", "web01", false); err != nil {
+		t.Fatalf("AddHostTemplateToHost: %s", err)
+	}
+
+	r, err := c.GetHost("web01")
+	if err != nil {
+		t.Fatalf("GetHost: %s", err)
+	}
+	h := client.DecodeHost(client.FirstObject(r))
+	if h.Address != "10.0.0.1" || h.Alias != "Web" {
+		t.Errorf("GetHost: got %+v", h)
+	}
+	if strings.Join(h.Templates, ",") != "GENERIC_HOST,LINUX" {
+		t.Errorf("templates: got %v", h.Templates)
+	}
+	if strings.Join(h.ContactGroups, ",") != "ops" {
+		t.Errorf("contact groups: got %v", h.ContactGroups)
+	}
+	if _, err := c.DeleteHostTemplateToHost("GENERIC_HOST", "web01", false); err != nil {
+		t.Fatalf("DeleteHostTemplateToHost: %s", err)
+	}
+	if _, err := c.DeleteHostTemplateToHost("GENERIC_HOST", "web01", false); err == nil {
+		t.Fatal("DeleteHostTemplateToHost unlinked template: expected error")
+	}
+	if tpl := stringsOf(s.Get(Hosts, "web01"), "templates"); strings.Join(tpl, ",") != "LINUX" {
+		t.Errorf("templates after delete: got %v", tpl)
+	}
+
+	s.Put(Contacts, "alice", Object{})
+	if _, err := c.AddContactToHost("alice", "web01", false); err != nil {
+		t.Fatalf("AddContactToHost: %s", err)
+	}
+	if _, err := c.AddContactToHost("nobody", "web01", false); err == nil {
+		t.Fatal("AddContactToHost unknown contact: expected error")
+	}
+	if _, err := c.DeleteContactGroupToHost("ops", "web01", false); err != nil {
+		t.Fatalf("DeleteContactGroupToHost: %s", err)
+	}
+	if _, err := c.DeleteContactGroupToHost("ops", "web01", false); err == nil {
+		t.Fatal("DeleteContactGroupToHost unlinked group: expected error")
+	}
+	if r, err = c.GetHost("web01"); err != nil {
+		t.Fatalf("GetHost: %s", err)
+	}
+	h = client.DecodeHost(client.FirstObject(r))
+	if strings.Join(h.Contacts, ",") != "alice" || len(h.ContactGroups) != 0 {
+		t.Errorf("links: got contacts %v, contact groups %v", h.Contacts, h.ContactGroups)
+	}
+
+	if _, err := c.AddCustomArgumentsToHost("web01", map[string]string{"_CMDB_ID": "CI1", "_SNMP_COMMUNITY": "s3cret"}, false); err != nil {
+		t.Fatalf("AddCustomArgumentsToHost: %s", err)
+	}
+	if _, err := c.AddCustomArgumentsToHost("web01", map[string]string{"_CMDB_ID": "CI2"}, false); err == nil {
+		t.Fatal("AddCustomArgumentsToHost existing variable: expected error")
+	}
+	if _, err := c.ModifyCustomArgumentsToHost("web01", map[string]string{"_CMDB_ID": "CI2"}, false); err != nil {
+		t.Fatalf("ModifyCustomArgumentsToHost: %s", err)
+	}
+	if _, err := c.DeleteCustomArgumentsToHost("web01", []string{"_SNMP_COMMUNITY"}, false); err != nil {
+		t.Fatalf("DeleteCustomArgumentsToHost: %s", err)
+	}
+	if _, err := c.DeleteCustomArgumentsToHost("web01", []string{"_SNMP_COMMUNITY"}, false); err == nil {
+		t.Fatal("DeleteCustomArgumentsToHost missing variable: expected error")
+	}
+	if r, err = c.GetHost("web01"); err != nil {
+		t.Fatalf("GetHost: %s", err)
+	}
+	h = client.DecodeHost(client.FirstObject(r))
+	if len(h.CustomVariables) != 1 || h.CustomVariables["_CMDB_ID"] != "CI2" {
+		t.Errorf("custom variables: got %v", h.CustomVariables)
+	}
+
+	if _, err := c.AddParentToHost("web01", "web01", false); err == nil {
+		t.Fatal("AddParentToHost self: expected error")
+	}
+	if _, err := c.AddParentToHost("router", "web01", false); err == nil {
+		t.Fatal("AddParentToHost unknown host: expected error")
+	}
+	s.Put(Hosts, "router", Object{})
+	if _, err := c.AddParentToHost("router", "web01", false); err != nil {
+		t.Fatalf("AddParentToHost: %s", err)
+	}
+	if p := stringsOf(s.Get(Hosts, "web01"), "parents"); strings.Join(p, ",") != "router" {
+		t.Errorf("parents: got %v", p)
+	}
+	if _, err := c.DeleteParentToHost("router", "web01", false); err != nil {
+		t.Fatalf("DeleteParentToHost: %s", err)
+	}
+	s.Delete(Hosts, "router")
+
+	params := map[string]string{"max_check_attempts": "5", "notification_period": "workhours"}
+	if _, err := c.ModifyHostParameters("web01", params, false); err != nil {
+		t.Fatalf("ModifyHostParameters: %s", err)
+	}
+	if _, err := c.ModifyHostParameters("web01", map[string]string{"max_check_attempts": ""}, false); err != nil {
+		t.Fatalf("ModifyHostParameters reset: %s", err)
+	}
+	if _, err := c.ModifyHostParameters("web01", map[string]string{"check_command": "x"}, false); err == nil {
+		t.Fatal("ModifyHostParameters unknown parameter: expected error")
+	}
+	if r, err = c.GetHost("web01"); err != nil {
+		t.Fatalf("GetHost: %s", err)
+	}
+	h = client.DecodeHost(client.FirstObject(r))
+	if len(h.Parameters) != 1 || h.Parameters["notification_period"] != "workhours" {
+		t.Errorf("parameters: got %v", h.Parameters)
+	}
+
+	hosts, err := c.ListHosts()
+	if err != nil || len(hosts) != 1 || hosts[0].Name != "web01" {
+		t.Fatalf("ListHosts: got %v, %v", hosts, err)
+	}
+
+	if _, err := c.DeleteHost("web01", false); err != nil {
+		t.Fatalf("DeleteHost: %s", err)
+	}
+	_, err = c.GetHost("web01")
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("GetHost after delete: got %v, want 404 error", err)
+	}
+}
+
+func TestCommandRename(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	if _, err := c.AddCommand("check_a", "$USER1$/check_a", "A"); err != nil {
+		t.Fatalf("AddCommand: %s", err)
+	}
+	if _, err := c.ModifyCommand("check_a", "check_b", "$USER1$/check_b", "B"); err != nil {
+		t.Fatalf("ModifyCommand: %s", err)
+	}
+	if s.Get(Commands, "check_a") != nil {
+		t.Error("old command name still present")
+	}
+	r, err := c.GetCommand("check_b")
+	if err != nil {
+		t.Fatalf("GetCommand: %s", err)
+	}
+	if cmd := client.DecodeCommand(client.FirstObject(r)); cmd.CommandLine != "$USER1$/check_b" || cmd.Description != "B" {
+		t.Errorf("GetCommand: got %+v", cmd)
+	}
+}
+
+func TestContactGroupMembership(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	body := map[string]interface{}{"contactName": "alice", "contactMail": "a@example.com", "contactGroup": "ops"}
+	if _, err := c.CreateContact(body); err == nil {
+		t.Fatal("CreateContact into missing group: expected error")
+	}
+	if _, err := c.CreateContactGroup("ops", "Operations", false); err != nil {
+		t.Fatalf("CreateContactGroup: %s", err)
+	}
+	if _, err := c.CreateContact(body); err != nil {
+		t.Fatalf("CreateContact: %s", err)
+	}
+	if _, err := c.ModifyContact(map[string]interface{}{"contactName": "alice", "newContactName": "alice2"}); err != nil {
+		t.Fatalf("ModifyContact: %s", err)
+	}
+
+	groups, err := c.ListContactGroups()
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("ListContactGroups: got %v, %v", groups, err)
+	}
+	if g := groups[0]; g.Description != "Operations" || strings.Join(g.Members, ",") != "alice2" {
+		t.Errorf("group: got %+v", g)
+	}
+
+	if _, err := c.CreateContactGroup("dev", "Developers", false); err != nil {
+		t.Fatalf("CreateContactGroup: %s", err)
+	}
+	if _, err := c.AddContactGroupToContact("dev", "alice2", false); err != nil {
+		t.Fatalf("AddContactGroupToContact: %s", err)
+	}
+	if _, err := c.AddContactGroupToContact("nobody", "alice2", false); err == nil {
+		t.Fatal("AddContactGroupToContact unknown group: expected error")
+	}
+	if _, err := c.DeleteContactGroupToContact("ops", "alice2", false); err != nil {
+		t.Fatalf("DeleteContactGroupToContact: %s", err)
+	}
+	if _, err := c.DeleteContactGroupToContact("ops", "alice2", false); err == nil {
+		t.Fatal("DeleteContactGroupToContact non-member: expected error")
+	}
+	r, err := c.GetContact("alice2")
+	if err != nil {
+		t.Fatalf("GetContact: %s", err)
+	}
+	if g := client.DecodeContact(client.FirstObject(r)).ContactGroups; strings.Join(g, ",") != "dev" {
+		t.Errorf("contact groups: got %v", g)
+	}
+
+	if _, err := c.DeleteContact("alice2"); err != nil {
+		t.Fatalf("DeleteContact: %s", err)
+	}
+	if m := s.Get(ContactGroups, "dev")["members"].([]string); len(m) != 0 {
+		t.Errorf("members after delete: got %v", m)
+	}
+}
+
+func TestTimeperiodLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	body := func(name string, exclude ...string) map[string]interface{} {
+		return map[string]interface{}{
+			"timeperiodName": name, "alias": name,
+			"weekdays":   map[string]string{"monday": "09:00-17:00"},
+			"exceptions": map[string]string{"december 25": "00:00-00:00"},
+			"exclude":    exclude,
+		}
+	}
+	if _, err := c.CreateTimeperiod(body("holidays")); err != nil {
+		t.Fatalf("CreateTimeperiod: %s", err)
+	}
+	if _, err := c.CreateTimeperiod(body("workhours", "holidays")); err != nil {
+		t.Fatalf("CreateTimeperiod: %s", err)
+	}
+	if _, err := c.DeleteTimeperiod("holidays"); err == nil {
+		t.Fatal("DeleteTimeperiod of an excluded timeperiod: expected error")
+	}
+
+	b := body("holidays")
+	b["newTimeperiodName"] = "holidays-fr"
+	if _, err := c.ModifyTimeperiod(b); err != nil {
+		t.Fatalf("ModifyTimeperiod: %s", err)
+	}
+	r, err := c.GetTimeperiod("workhours")
+	if err != nil {
+		t.Fatalf("GetTimeperiod: %s", err)
+	}
+	tp := client.DecodeTimeperiod(client.FirstObject(r))
+	if tp.Weekdays["monday"] != "09:00-17:00" || tp.Exceptions["december 25"] != "00:00-00:00" {
+		t.Errorf("GetTimeperiod: got %+v", tp)
+	}
+	if strings.Join(tp.Exclude, ",") != "holidays-fr" {
+		t.Errorf("exclude after rename: got %v", tp.Exclude)
+	}
+
+	if _, err := c.DeleteTimeperiod("workhours"); err != nil {
+		t.Fatalf("DeleteTimeperiod: %s", err)
+	}
+	if _, err := c.DeleteTimeperiod("holidays-fr"); err != nil {
+		t.Fatalf("DeleteTimeperiod: %s", err)
+	}
+}
+
+func TestUserLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	user := map[string]interface{}{
+		"userName": "jdoe", "userGroup": "admins", "userMail": "jdoe@example.com",
+		"userType": "local", "userPassword": "s3cret", "createContact": true,
+	}
+	if _, err := c.CreateUser(user); err == nil {
+		t.Fatal("CreateUser into missing group: expected error")
+	}
+	if _, err := c.CreateUserGroup(map[string]interface{}{"groupName": "admins"}); err != nil {
+		t.Fatalf("CreateUserGroup: %s", err)
+	}
+	if _, err := c.CreateUser(user); err != nil {
+		t.Fatalf("CreateUser: %s", err)
+	}
+	r, err := c.GetUser("jdoe")
+	if err != nil {
+		t.Fatalf("GetUser: %s", err)
+	}
+	obj := client.FirstObject(r)
+	if _, ok := obj["user_passwd"]; ok {
+		t.Error("GetUser returned the password hash")
+	}
+	if u := client.DecodeUser(obj); u.Group != "admins" || u.AuthType != "local" || u.LinkedContact == nil || !*u.LinkedContact {
+		t.Errorf("GetUser: got %+v", u)
+	}
+	if s.Get(Contacts, "jdoe") == nil {
+		t.Error("linked contact not created")
+	}
+
+	if _, err := c.DeleteUserGroup("admins"); err == nil {
+		t.Fatal("DeleteUserGroup with users: expected error")
+	}
+	if _, err := c.DeleteUser("jdoe"); err != nil {
+		t.Fatalf("DeleteUser: %s", err)
+	}
+	if s.Get(Contacts, "jdoe") != nil {
+		t.Error("linked contact not deleted with the user")
+	}
+	if _, err := c.DeleteUserGroup("admins"); err != nil {
+		t.Fatalf("DeleteUserGroup: %s", err)
+	}
+}
+
+func TestExportJob(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	s.ExportOutput = "Warning: host web01 has no services\nError: command check_x not defined\n"
+	s.ExportFailed = true
+	if _, err := c.ExportConfiguration("tf"); err != nil {
+		t.Fatalf("ExportConfiguration: %s", err)
+	}
+	job, err := c.GetExportJob("tf")
+	if err != nil {
+		t.Fatalf("GetExportJob: %s", err)
+	}
+	if !job.Done() || !job.Failed() {
+		t.Errorf("job status: got %q", job.Status)
+	}
+	errs, warnings := client.ParseVerifyOutput(job.Output)
+	if len(errs) != 1 || len(warnings) != 1 {
+		t.Errorf("verify output: got errors %v, warnings %v", errs, warnings)
+	}
+}
+
+func TestExportJobQueued(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	s.ExportFailed = true
+	if _, err := c.ExportConfiguration("tf"); err != nil {
+		t.Fatalf("ExportConfiguration: %s", err)
+	}
+	prev, err := c.GetExportJob("tf")
+	if err != nil {
+		t.Fatalf("GetExportJob: %s", err)
+	}
+
+	s.ExportFailed, s.ExportQueued = false, 1
+	if _, err := c.ExportConfiguration("tf"); err != nil {
+		t.Fatalf("ExportConfiguration: %s", err)
+	}
+	job, err := c.GetExportJob("tf")
+	if err != nil {
+		t.Fatalf("GetExportJob: %s", err)
+	}
+	if job.Supersedes(prev) || !job.Failed() {
+		t.Errorf("first poll: got run %d (%s), want the previous run %d", job.ID, job.Status, prev.ID)
+	}
+	job, err = c.GetExportJob("tf")
+	if err != nil {
+		t.Fatalf("GetExportJob: %s", err)
+	}
+	if !job.Supersedes(prev) || job.Failed() {
+		t.Errorf("second poll: got run %d (%s), want a new finished run", job.ID, job.Status)
+	}
+}
+
+func TestUnknownEndpoint(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	_, err := s.Client().Post("noSuchEndpoint", map[string]string{})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("got %v, want 404 error", err)
+	}
+}
+
+func TestDriftHelpers(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.Put(Hosts, "db01", Object{"address": "10.0.0.2"})
+	s.Set(Hosts, "db01", "address", "10.0.0.3")
+	r, err := s.Client().GetHost("db01")
+	if err != nil {
+		t.Fatalf("GetHost: %s", err)
+	}
+	if h := client.DecodeHost(client.FirstObject(r)); h.Address != "10.0.0.3" {
+		t.Errorf("address: got %q", h.Address)
+	}
+	s.Delete(Hosts, "db01")
+	if s.Get(Hosts, "db01") != nil {
+		t.Error("host still present after Delete")
+	}
+	if n := s.Calls("getHost"); n != 1 {
+		t.Errorf("getHost calls: got %d, want 1", n)
+	}
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// autoExports holds the clients of provider configurations with auto_export
+// enabled. Terraform runs one provider process per operation, so Shutdown
+// exports at most once per configuration at the end of an apply.
+var autoExports struct {
+	mu   sync.Mutex
+	jobs []autoExport
+}
+
+type autoExport struct {
+	client  *client.Client
+	jobName string
+}
+
+func registerAutoExport(c *client.Client, jobName string) {
+	autoExports.mu.Lock()
+	defer autoExports.mu.Unlock()
+	autoExports.jobs = append(autoExports.jobs, autoExport{client: c, jobName: jobName})
+}
+
+// Shutdown runs exportConfiguration for every auto_export configuration
+// whose client changed something since its last export. It is called once
+// the plugin server has stopped. Terraform only grants a stopping plugin a
+// couple of seconds, so the export job is started but not waited for.
+func Shutdown(ctx context.Context) error {
+	autoExports.mu.Lock()
+	defer autoExports.mu.Unlock()
+
+	var firstErr error
+	for _, ae := range autoExports.jobs {
+		if !ae.client.Mutated() {
+			continue
+		}
+		tflog.Info(ctx, "Auto-exporting Nagios configuration", map[string]interface{}{"job": ae.jobName})
+		if _, err := ae.client.ExportConfiguration(ae.jobName); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	autoExports.jobs = nil
+	return firstErr
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/ktoulliou/terraform-provider-eon/internal/eontest"
+)
+
+func TestShutdownAutoExport(t *testing.T) {
+	s := eontest.NewServer()
+	defer s.Close()
+	ctx := context.Background()
+
+	changed, idle, broken := s.Client(), s.Client(), s.Client()
+	registerAutoExport(changed, "tf")
+	registerAutoExport(idle, "tf-idle")
+	registerAutoExport(broken, "") // rejected by exportConfiguration
+
+	if _, err := changed.CreateContactGroup("ops", "Ops", false); err != nil {
+		t.Fatalf("CreateContactGroup: %s", err)
+	}
+	if _, err := broken.CreateContactGroup("dev", "Dev", false); err != nil {
+		t.Fatalf("CreateContactGroup: %s", err)
+	}
+	// Runtime calls leave nothing to export, even when they fail.
+	if _, err := idle.CreateDowntime(client.Downtime{}); err == nil {
+		t.Fatal("CreateDowntime of an unknown host succeeded")
+	}
+
+	if err := Shutdown(ctx); err == nil {
+		t.Error("Shutdown: got no error for the failed export")
+	}
+	if got := s.Calls("exportConfiguration"); got != 2 {
+		t.Errorf("exportConfiguration called %d times, want 2 (changed and broken)", got)
+	}
+	if changed.Mutated() {
+		t.Error("changed client still mutated after a successful export")
+	}
+	if !broken.Mutated() {
+		t.Error("failed export cleared the mutated flag")
+	}
+
+	// Shutdown forgets the configurations it flushed.
+	if err := Shutdown(ctx); err != nil {
+		t.Errorf("second Shutdown: %s", err)
+	}
+	if got := s.Calls("exportConfiguration"); got != 2 {
+		t.Errorf("second Shutdown exported again: %d calls", got)
+	}
+}
@@ -3,10 +3,15 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"net"
+	"regexp"
+	"sort"
 
 	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -19,8 +24,14 @@ var _ datasource.DataSource = &hostDS{}
 type hostDS struct{ client *client.Client }
 
 type hostDSModel struct {
-	Name       types.String `tfsdk:"name"`
-	ResultJSON types.String `tfsdk:"result_json"`
+	Name          types.String `tfsdk:"name"`
+	IP            types.String `tfsdk:"ip"`
+	Alias         types.String `tfsdk:"alias"`
+	Templates     types.List   `tfsdk:"templates"`
+	Contacts      types.List   `tfsdk:"contacts"`
+	ContactGroups types.List   `tfsdk:"contact_groups"`
+	HostGroups    types.List   `tfsdk:"host_groups"`
+	ResultJSON    types.String `tfsdk:"result_json"`
 }
 
 func NewHostDataSource() datasource.DataSource { return &hostDS{} }
@@ -33,8 +44,14 @@ func (d *hostDS) Schema(_ context.Context, _ datasource.SchemaRequest, resp *dat
 	resp.Schema = schema.Schema{
 		Description: "Reads an existing Nagios host from EON.",
 		Attributes: map[string]schema.Attribute{
-			"name":        schema.StringAttribute{Required: true, Description: "Host name to look up."},
-			"result_json": schema.StringAttribute{Computed: true, Description: "Raw JSON result from EONAPI."},
+			"name":           schema.StringAttribute{Required: true, Description: "Host name to look up."},
+			"ip":             schema.StringAttribute{Computed: true, Description: "Host address."},
+			"alias":          schema.StringAttribute{Computed: true, Description: "Host alias."},
+			"templates":      schema.ListAttribute{Computed: true, ElementType: types.StringType, Description: "Host templates, in inheritance order."},
+			"contacts":       schema.ListAttribute{Computed: true, ElementType: types.StringType, Description: "Attached contacts."},
+			"contact_groups": schema.ListAttribute{Computed: true, ElementType: types.StringType, Description: "Attached contact groups."},
+			"host_groups":    schema.ListAttribute{Computed: true, ElementType: types.StringType, Description: "Host groups the host belongs to."},
+			"result_json":    schema.StringAttribute{Computed: true, Description: "Raw JSON result from EONAPI."},
 		},
 	}
 }
@@ -58,6 +75,14 @@ func (d *hostDS) Read(ctx context.Context, req datasource.ReadRequest, resp *dat
 	}
 	b, _ := json.Marshal(apiResp.Result)
 	cfg.ResultJSON = types.StringValue(string(b))
+
+	h := client.DecodeHost(client.FirstObject(apiResp))
+	cfg.IP = types.StringValue(h.Address)
+	cfg.Alias = types.StringValue(h.Alias)
+	cfg.Templates = stringListValue(h.Templates)
+	cfg.Contacts = stringListValue(h.Contacts)
+	cfg.ContactGroups = stringListValue(h.ContactGroups)
+	cfg.HostGroups = stringListValue(h.HostGroups)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &cfg)...)
 }
 
@@ -70,8 +95,10 @@ var _ datasource.DataSource = &commandDS{}
 type commandDS struct{ client *client.Client }
 
 type commandDSModel struct {
-	Name       types.String `tfsdk:"name"`
-	ResultJSON types.String `tfsdk:"result_json"`
+	Name        types.String `tfsdk:"name"`
+	CommandLine types.String `tfsdk:"command_line"`
+	Description types.String `tfsdk:"description"`
+	ResultJSON  types.String `tfsdk:"result_json"`
 }
 
 func NewCommandDataSource() datasource.DataSource { return &commandDS{} }
@@ -84,8 +111,10 @@ func (d *commandDS) Schema(_ context.Context, _ datasource.SchemaRequest, resp *
 	resp.Schema = schema.Schema{
 		Description: "Reads an existing Nagios command from EON.",
 		Attributes: map[string]schema.Attribute{
-			"name":        schema.StringAttribute{Required: true, Description: "Command name to look up."},
-			"result_json": schema.StringAttribute{Computed: true, Description: "Raw JSON result from EONAPI."},
+			"name":         schema.StringAttribute{Required: true, Description: "Command name to look up."},
+			"command_line": schema.StringAttribute{Computed: true, Description: "Full command line."},
+			"description":  schema.StringAttribute{Computed: true, Description: "Human-readable description."},
+			"result_json":  schema.StringAttribute{Computed: true, Description: "Raw JSON result from EONAPI."},
 		},
 	}
 }
@@ -109,5 +138,170 @@ func (d *commandDS) Read(ctx context.Context, req datasource.ReadRequest, resp *
 	}
 	b, _ := json.Marshal(apiResp.Result)
 	cfg.ResultJSON = types.StringValue(string(b))
+
+	cmd := client.DecodeCommand(client.FirstObject(apiResp))
+	cfg.CommandLine = types.StringValue(cmd.CommandLine)
+	cfg.Description = types.StringValue(cmd.Description)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &cfg)...)
 }
+
+// ═══════════════════════════════════════════════════════════════════════════
+//  data "eon_hosts"
+// ═══════════════════════════════════════════════════════════════════════════
+
+var _ datasource.DataSource = &hostsDS{}
+
+type hostsDS struct{ client *client.Client }
+
+type hostsDSModel struct {
+	Template    types.String       `tfsdk:"template"`
+	HostGroup   types.String       `tfsdk:"host_group"`
+	NameRegex   types.String       `tfsdk:"name_regex"`
+	AddressCIDR types.String       `tfsdk:"address_cidr"`
+	Hosts       []hostsDSItemModel `tfsdk:"hosts"`
+}
+
+type hostsDSItemModel struct {
+	Name          types.String `tfsdk:"name"`
+	IP            types.String `tfsdk:"ip"`
+	Alias         types.String `tfsdk:"alias"`
+	Templates     types.List   `tfsdk:"templates"`
+	HostGroups    types.List   `tfsdk:"host_groups"`
+	Contacts      types.List   `tfsdk:"contacts"`
+	ContactGroups types.List   `tfsdk:"contact_groups"`
+}
+
+func NewHostsDataSource() datasource.DataSource { return &hostsDS{} }
+
+func (d *hostsDS) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hosts"
+}
+
+func (d *hostsDS) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists Nagios hosts monitored by EON (listNagiosObjects), optionally filtered.",
+		Attributes: map[string]schema.Attribute{
+			"template":     schema.StringAttribute{Optional: true, Description: "Only hosts using this host template."},
+			"host_group":   schema.StringAttribute{Optional: true, Description: "Only hosts member of this host group."},
+			"name_regex":   schema.StringAttribute{Optional: true, Description: "Only hosts whose name matches this RE2 regular expression."},
+			"address_cidr": schema.StringAttribute{Optional: true, Description: "Only hosts whose address is an IP inside this CIDR block."},
+			"hosts": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Matching hosts, sorted by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name":           schema.StringAttribute{Computed: true},
+						"ip":             schema.StringAttribute{Computed: true},
+						"alias":          schema.StringAttribute{Computed: true},
+						"templates":      schema.ListAttribute{Computed: true, ElementType: types.StringType},
+						"host_groups":    schema.ListAttribute{Computed: true, ElementType: types.StringType},
+						"contacts":       schema.ListAttribute{Computed: true, ElementType: types.StringType},
+						"contact_groups": schema.ListAttribute{Computed: true, ElementType: types.StringType},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *hostsDS) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		d.client = req.ProviderData.(*client.Client)
+	}
+}
+
+func (d *hostsDS) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var cfg hostsDSModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRE *regexp.Regexp
+	if v := cfg.NameRegex.ValueString(); v != "" {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid name_regex", err.Error())
+			return
+		}
+		nameRE = re
+	}
+	var cidr *net.IPNet
+	if v := cfg.AddressCIDR.ValueString(); v != "" {
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("address_cidr"), "Invalid address_cidr", err.Error())
+			return
+		}
+		cidr = n
+	}
+
+	hosts, err := d.client.ListHosts()
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing hosts", err.Error())
+		return
+	}
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Name < hosts[j].Name })
+
+	cfg.Hosts = []hostsDSItemModel{}
+	for _, h := range hosts {
+		if t := cfg.Template.ValueString(); t != "" && !containsString(h.Templates, t) {
+			continue
+		}
+		if g := cfg.HostGroup.ValueString(); g != "" && !containsString(h.HostGroups, g) {
+			continue
+		}
+		if nameRE != nil && !nameRE.MatchString(h.Name) {
+			continue
+		}
+		if cidr != nil {
+			ip := net.ParseIP(h.Address)
+			if ip == nil || !cidr.Contains(ip) {
+				continue
+			}
+		}
+		cfg.Hosts = append(cfg.Hosts, hostsDSItemModel{
+			Name:          types.StringValue(h.Name),
+			IP:            types.StringValue(h.Address),
+			Alias:         types.StringValue(h.Alias),
+			Templates:     stringListValue(h.Templates),
+			HostGroups:    stringListValue(h.HostGroups),
+			Contacts:      stringListValue(h.Contacts),
+			ContactGroups: stringListValue(h.ContactGroups),
+		})
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &cfg)...)
+}
+
+func stringListValue(v []string) types.List {
+	elems := make([]attr.Value, 0, len(v))
+	for _, s := range v {
+		elems = append(elems, types.StringValue(s))
+	}
+	return types.ListValueMust(types.StringType, elems)
+}
+
+func stringSetValue(v []string) types.Set {
+	elems := make([]attr.Value, 0, len(v))
+	for _, s := range v {
+		elems = append(elems, types.StringValue(s))
+	}
+	return types.SetValueMust(types.StringType, elems)
+}
+
+func stringMapValue(v map[string]string) types.Map {
+	elems := make(map[string]attr.Value, len(v))
+	for k, s := range v {
+		elems[k] = types.StringValue(s)
+	}
+	return types.MapValueMust(types.StringType, elems)
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
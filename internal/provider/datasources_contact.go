@@ -0,0 +1,286 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var contactDSAttributes = map[string]schema.Attribute{
+	"alias":          schema.StringAttribute{Computed: true, Description: "Contact alias."},
+	"mail":           schema.StringAttribute{Computed: true, Description: "Contact email address."},
+	"pager":          schema.StringAttribute{Computed: true, Description: "Pager number/address."},
+	"contact_groups": schema.ListAttribute{Computed: true, ElementType: types.StringType, Description: "Contact groups the contact belongs to."},
+}
+
+var contactGroupDSAttributes = map[string]schema.Attribute{
+	"description": schema.StringAttribute{Computed: true, Description: "Group description."},
+	"members":     schema.ListAttribute{Computed: true, ElementType: types.StringType, Description: "Member contacts."},
+}
+
+// withName returns attrs plus a "name" attribute built by nameAttr.
+func withName(attrs map[string]schema.Attribute, nameAttr schema.Attribute) map[string]schema.Attribute {
+	out := map[string]schema.Attribute{"name": nameAttr}
+	for k, v := range attrs {
+		out[k] = v
+	}
+	return out
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+//  data "eon_contact"
+// ═══════════════════════════════════════════════════════════════════════════
+
+var _ datasource.DataSource = &contactDS{}
+
+type contactDS struct{ client *client.Client }
+
+type contactDSModel struct {
+	Name          types.String `tfsdk:"name"`
+	Alias         types.String `tfsdk:"alias"`
+	Mail          types.String `tfsdk:"mail"`
+	Pager         types.String `tfsdk:"pager"`
+	ContactGroups types.List   `tfsdk:"contact_groups"`
+}
+
+func contactDSValue(c client.Contact) contactDSModel {
+	return contactDSModel{
+		Name:          types.StringValue(c.Name),
+		Alias:         types.StringValue(c.Alias),
+		Mail:          types.StringValue(c.Mail),
+		Pager:         types.StringValue(c.Pager),
+		ContactGroups: stringListValue(c.ContactGroups),
+	}
+}
+
+func NewContactDataSource() datasource.DataSource { return &contactDS{} }
+
+func (d *contactDS) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_contact"
+}
+
+func (d *contactDS) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads an existing Nagios contact from EON.",
+		Attributes: withName(contactDSAttributes,
+			schema.StringAttribute{Required: true, Description: "Contact name to look up."}),
+	}
+}
+
+func (d *contactDS) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		d.client = req.ProviderData.(*client.Client)
+	}
+}
+
+func (d *contactDS) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var cfg contactDSModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	apiResp, err := d.client.GetContact(cfg.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading contact", err.Error())
+		return
+	}
+	state := contactDSValue(client.DecodeContact(client.FirstObject(apiResp)))
+	state.Name = cfg.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+//  data "eon_contacts"
+// ═══════════════════════════════════════════════════════════════════════════
+
+var _ datasource.DataSource = &contactsDS{}
+
+type contactsDS struct{ client *client.Client }
+
+type contactsDSModel struct {
+	ContactGroup types.String     `tfsdk:"contact_group"`
+	Contacts     []contactDSModel `tfsdk:"contacts"`
+}
+
+func NewContactsDataSource() datasource.DataSource { return &contactsDS{} }
+
+func (d *contactsDS) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_contacts"
+}
+
+func (d *contactsDS) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists Nagios contacts known to EON (listNagiosObjects).",
+		Attributes: map[string]schema.Attribute{
+			"contact_group": schema.StringAttribute{Optional: true, Description: "Only contacts member of this contact group."},
+			"contacts": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Matching contacts, sorted by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: withName(contactDSAttributes, schema.StringAttribute{Computed: true}),
+				},
+			},
+		},
+	}
+}
+
+func (d *contactsDS) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		d.client = req.ProviderData.(*client.Client)
+	}
+}
+
+func (d *contactsDS) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var cfg contactsDSModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	contacts, err := d.client.ListContacts()
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing contacts", err.Error())
+		return
+	}
+	sort.Slice(contacts, func(i, j int) bool { return contacts[i].Name < contacts[j].Name })
+
+	// livestatus only lists membership on the group side.
+	var members []string
+	g := cfg.ContactGroup.ValueString()
+	if g != "" {
+		groups, err := d.client.ListContactGroups()
+		if err != nil {
+			resp.Diagnostics.AddError("Error listing contact groups", err.Error())
+			return
+		}
+		for _, grp := range groups {
+			if grp.Name == g {
+				members = grp.Members
+			}
+		}
+	}
+
+	cfg.Contacts = []contactDSModel{}
+	for _, c := range contacts {
+		if g != "" && !containsString(c.ContactGroups, g) && !containsString(members, c.Name) {
+			continue
+		}
+		cfg.Contacts = append(cfg.Contacts, contactDSValue(c))
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &cfg)...)
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+//  data "eon_contact_group"
+// ═══════════════════════════════════════════════════════════════════════════
+
+var _ datasource.DataSource = &contactGroupDS{}
+
+type contactGroupDS struct{ client *client.Client }
+
+type contactGroupDSModel struct {
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Members     types.List   `tfsdk:"members"`
+}
+
+func contactGroupDSValue(g client.ContactGroup) contactGroupDSModel {
+	return contactGroupDSModel{
+		Name:        types.StringValue(g.Name),
+		Description: types.StringValue(g.Description),
+		Members:     stringListValue(g.Members),
+	}
+}
+
+func NewContactGroupDataSource() datasource.DataSource { return &contactGroupDS{} }
+
+func (d *contactGroupDS) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_contact_group"
+}
+
+func (d *contactGroupDS) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads an existing Nagios contact group from EON.",
+		Attributes: withName(contactGroupDSAttributes,
+			schema.StringAttribute{Required: true, Description: "Contact group name to look up."}),
+	}
+}
+
+func (d *contactGroupDS) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		d.client = req.ProviderData.(*client.Client)
+	}
+}
+
+func (d *contactGroupDS) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var cfg contactGroupDSModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	apiResp, err := d.client.GetContactGroup(cfg.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading contact group", err.Error())
+		return
+	}
+	state := contactGroupDSValue(client.DecodeContactGroup(client.FirstObject(apiResp)))
+	state.Name = cfg.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+//  data "eon_contact_groups"
+// ═══════════════════════════════════════════════════════════════════════════
+
+var _ datasource.DataSource = &contactGroupsDS{}
+
+type contactGroupsDS struct{ client *client.Client }
+
+type contactGroupsDSModel struct {
+	ContactGroups []contactGroupDSModel `tfsdk:"contact_groups"`
+}
+
+func NewContactGroupsDataSource() datasource.DataSource { return &contactGroupsDS{} }
+
+func (d *contactGroupsDS) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_contact_groups"
+}
+
+func (d *contactGroupsDS) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists Nagios contact groups known to EON (listNagiosObjects).",
+		Attributes: map[string]schema.Attribute{
+			"contact_groups": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "All contact groups, sorted by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: withName(contactGroupDSAttributes, schema.StringAttribute{Computed: true}),
+				},
+			},
+		},
+	}
+}
+
+func (d *contactGroupsDS) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		d.client = req.ProviderData.(*client.Client)
+	}
+}
+
+func (d *contactGroupsDS) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	groups, err := d.client.ListContactGroups()
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing contact groups", err.Error())
+		return
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+
+	state := contactGroupsDSModel{ContactGroups: []contactGroupDSModel{}}
+	for _, g := range groups {
+		state.ContactGroups = append(state.ContactGroups, contactGroupDSValue(g))
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
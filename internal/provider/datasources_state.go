@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	hostStateNames    = []string{"UP", "DOWN", "UNREACHABLE"}
+	serviceStateNames = []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+)
+
+func stateName(names []string, state int64) string {
+	if state >= 0 && int(state) < len(names) {
+		return names[state]
+	}
+	return fmt.Sprintf("%d", state)
+}
+
+func lastCheckValue(ts int64) types.String {
+	if ts <= 0 {
+		return types.StringValue("")
+	}
+	return types.StringValue(time.Unix(ts, 0).UTC().Format(time.RFC3339))
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+//  data "eon_host_state"
+// ═══════════════════════════════════════════════════════════════════════════
+
+var _ datasource.DataSource = &hostStateDS{}
+
+type hostStateDS struct{ client *client.Client }
+
+type hostStateDSModel struct {
+	Name         types.String `tfsdk:"name"`
+	State        types.String `tfsdk:"state"`
+	StateID      types.Int64  `tfsdk:"state_id"`
+	Output       types.String `tfsdk:"output"`
+	LastCheck    types.String `tfsdk:"last_check"`
+	Acknowledged types.Bool   `tfsdk:"acknowledged"`
+	InDowntime   types.Bool   `tfsdk:"in_downtime"`
+}
+
+func NewHostStateDataSource() datasource.DataSource { return &hostStateDS{} }
+
+func (d *hostStateDS) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_host_state"
+}
+
+func (d *hostStateDS) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads the current monitoring state of a host (listNagiosStates).",
+		Attributes: map[string]schema.Attribute{
+			"name":         schema.StringAttribute{Required: true, Description: "Host name."},
+			"state":        schema.StringAttribute{Computed: true, Description: "UP, DOWN or UNREACHABLE."},
+			"state_id":     schema.Int64Attribute{Computed: true, Description: "Numeric Nagios state (0-2)."},
+			"output":       schema.StringAttribute{Computed: true, Description: "Plugin output of the last check."},
+			"last_check":   schema.StringAttribute{Computed: true, Description: "Time of the last check (RFC 3339), empty if never checked."},
+			"acknowledged": schema.BoolAttribute{Computed: true, Description: "Whether the current problem is acknowledged."},
+			"in_downtime":  schema.BoolAttribute{Computed: true, Description: "Whether the host is in a scheduled downtime."},
+		},
+	}
+}
+
+func (d *hostStateDS) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		d.client = req.ProviderData.(*client.Client)
+	}
+}
+
+func (d *hostStateDS) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var cfg hostStateDSModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	name := cfg.Name.ValueString()
+	st, err := d.client.GetState(name, "")
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading host state", err.Error())
+		return
+	}
+	if st == nil {
+		resp.Diagnostics.AddError("Host not found", fmt.Sprintf("No monitoring state for host %q.", name))
+		return
+	}
+	cfg.State = types.StringValue(stateName(hostStateNames, st.State))
+	cfg.StateID = types.Int64Value(st.State)
+	cfg.Output = types.StringValue(st.Output)
+	cfg.LastCheck = lastCheckValue(st.LastCheck)
+	cfg.Acknowledged = types.BoolValue(st.Acknowledged)
+	cfg.InDowntime = types.BoolValue(st.InDowntime)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &cfg)...)
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+//  data "eon_service_states"
+// ═══════════════════════════════════════════════════════════════════════════
+
+var _ datasource.DataSource = &serviceStatesDS{}
+
+type serviceStatesDS struct{ client *client.Client }
+
+type serviceStatesDSModel struct {
+	HostName  types.String              `tfsdk:"host_name"`
+	HostGroup types.String              `tfsdk:"host_group"`
+	State     types.String              `tfsdk:"state"`
+	Services  []serviceStateDSItemModel `tfsdk:"services"`
+}
+
+type serviceStateDSItemModel struct {
+	HostName     types.String `tfsdk:"host_name"`
+	Description  types.String `tfsdk:"description"`
+	State        types.String `tfsdk:"state"`
+	StateID      types.Int64  `tfsdk:"state_id"`
+	Output       types.String `tfsdk:"output"`
+	LastCheck    types.String `tfsdk:"last_check"`
+	Acknowledged types.Bool   `tfsdk:"acknowledged"`
+	InDowntime   types.Bool   `tfsdk:"in_downtime"`
+}
+
+func NewServiceStatesDataSource() datasource.DataSource { return &serviceStatesDS{} }
+
+func (d *serviceStatesDS) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_states"
+}
+
+func (d *serviceStatesDS) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists current service states (listNagiosStates), e.g. to gate a deployment on monitoring health.",
+		Attributes: map[string]schema.Attribute{
+			"host_name":  schema.StringAttribute{Optional: true, Description: "Only services of this host."},
+			"host_group": schema.StringAttribute{Optional: true, Description: "Only services of hosts in this host group."},
+			"state":      schema.StringAttribute{Optional: true, Description: "Only services in this state: OK, WARNING, CRITICAL or UNKNOWN."},
+			"services": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Matching services, sorted by host then description.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"host_name":    schema.StringAttribute{Computed: true},
+						"description":  schema.StringAttribute{Computed: true},
+						"state":        schema.StringAttribute{Computed: true},
+						"state_id":     schema.Int64Attribute{Computed: true},
+						"output":       schema.StringAttribute{Computed: true},
+						"last_check":   schema.StringAttribute{Computed: true},
+						"acknowledged": schema.BoolAttribute{Computed: true},
+						"in_downtime":  schema.BoolAttribute{Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *serviceStatesDS) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		d.client = req.ProviderData.(*client.Client)
+	}
+}
+
+func (d *serviceStatesDS) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var cfg serviceStatesDSModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var filters []string
+	if v := cfg.HostName.ValueString(); v != "" {
+		filters = append(filters, client.LivestatusFilter("host_name", "=", v))
+	}
+	if v := cfg.HostGroup.ValueString(); v != "" {
+		filters = append(filters, client.LivestatusFilter("host_groups", ">=", v))
+	}
+	if v := cfg.State.ValueString(); v != "" {
+		id := -1
+		for i, n := range serviceStateNames {
+			if strings.EqualFold(n, v) {
+				id = i
+			}
+		}
+		if id < 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("state"), "Invalid state",
+				fmt.Sprintf("%q is not one of %s.", v, strings.Join(serviceStateNames, ", ")))
+			return
+		}
+		filters = append(filters, fmt.Sprintf("Filter: state = %d", id))
+	}
+
+	states, err := d.client.ListNagiosStates("services", filters...)
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing service states", err.Error())
+		return
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].HostName != states[j].HostName {
+			return states[i].HostName < states[j].HostName
+		}
+		return states[i].Description < states[j].Description
+	})
+
+	cfg.Services = []serviceStateDSItemModel{}
+	for _, st := range states {
+		cfg.Services = append(cfg.Services, serviceStateDSItemModel{
+			HostName:     types.StringValue(st.HostName),
+			Description:  types.StringValue(st.Description),
+			State:        types.StringValue(stateName(serviceStateNames, st.State)),
+			StateID:      types.Int64Value(st.State),
+			Output:       types.StringValue(st.Output),
+			LastCheck:    lastCheckValue(st.LastCheck),
+			Acknowledged: types.BoolValue(st.Acknowledged),
+			InDowntime:   types.BoolValue(st.InDowntime),
+		})
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &cfg)...)
+}
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/eontest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccHostDataSources(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "eon_host" "test" {
+  name  = "tfacc-ds-host"
+  ip    = "10.98.0.1"
+  alias = "Data source host"
+}
+
+data "eon_host" "test" {
+  name = eon_host.test.name
+}
+
+data "eon_hosts" "test" {
+  name_regex   = "^tfacc-ds-"
+  address_cidr = "10.98.0.0/16"
+  depends_on   = [eon_host.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.eon_host.test", "ip", "10.98.0.1"),
+					resource.TestCheckResourceAttr("data.eon_host.test", "alias", "Data source host"),
+					resource.TestCheckResourceAttrSet("data.eon_host.test", "result_json"),
+					resource.TestCheckResourceAttr("data.eon_hosts.test", "hosts.#", "1"),
+					resource.TestCheckResourceAttr("data.eon_hosts.test", "hosts.0.name", "tfacc-ds-host"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCommandDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "eon_command" "test" {
+  name         = "tfacc_ds_check"
+  command_line = "$USER1$/check_tcp -H $HOSTADDRESS$ -p $ARG1$"
+  description  = "tcp"
+}
+
+data "eon_command" "test" {
+  name = eon_command.test.name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.eon_command.test", "command_line", "$USER1$/check_tcp -H $HOSTADDRESS$ -p $ARG1$"),
+					resource.TestCheckResourceAttr("data.eon_command.test", "description", "tcp"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccContactDataSources(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "eon_contact_group" "test" {
+  name        = "tfacc-ds-group"
+  description = "DS group"
+}
+
+resource "eon_contact" "test" {
+  name           = "tfacc-ds-contact"
+  mail           = "ds@example.com"
+  contact_groups = [eon_contact_group.test.name]
+}
+
+data "eon_contact" "test" {
+  name = eon_contact.test.name
+}
+
+data "eon_contact_group" "test" {
+  name       = eon_contact_group.test.name
+  depends_on = [eon_contact.test]
+}
+
+data "eon_contacts" "test" {
+  contact_group = eon_contact_group.test.name
+  depends_on    = [eon_contact.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.eon_contact.test", "mail", "ds@example.com"),
+					resource.TestCheckResourceAttr("data.eon_contact_group.test", "description", "DS group"),
+					resource.TestCheckTypeSetElemAttr("data.eon_contact_group.test", "members.*", "tfacc-ds-contact"),
+					resource.TestCheckResourceAttr("data.eon_contacts.test", "contacts.#", "1"),
+					resource.TestCheckResourceAttr("data.eon_contacts.test", "contacts.0.name", "tfacc-ds-contact"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStateDataSources(t *testing.T) {
+	testAccLocalOnly(t)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "eon_service_states" "test" {
+  state = "BROKEN"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid state`),
+			},
+			{
+				PreConfig: func() {
+					testAccServer.Put(eontest.Hosts, "tfacc-st-web", eontest.Object{"groups": []string{"tfacc-prod"}})
+					testAccServer.Put(eontest.Hosts, "tfacc-st-db", eontest.Object{"groups": []string{"tfacc-lab"}})
+					testAccServer.SetState("tfacc-st-web", "", 1, "CRITICAL - Host Unreachable")
+					testAccServer.SetState("tfacc-st-web", "HTTP", 2, "HTTP CRITICAL - 503")
+					testAccServer.SetState("tfacc-st-web", "Disk", 0, "DISK OK")
+					testAccServer.SetState("tfacc-st-db", "Disk", 2, "DISK CRITICAL")
+				},
+				Config: `
+data "eon_host_state" "web" {
+  name = "tfacc-st-web"
+}
+
+data "eon_host_state" "db" {
+  name = "tfacc-st-db"
+}
+
+data "eon_service_states" "web" {
+  host_name = "tfacc-st-web"
+}
+
+data "eon_service_states" "prod_critical" {
+  host_group = "tfacc-prod"
+  state      = "CRITICAL"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.eon_host_state.web", "state", "DOWN"),
+					resource.TestCheckResourceAttr("data.eon_host_state.web", "state_id", "1"),
+					resource.TestCheckResourceAttr("data.eon_host_state.web", "output", "CRITICAL - Host Unreachable"),
+					resource.TestCheckResourceAttrSet("data.eon_host_state.web", "last_check"),
+					resource.TestCheckResourceAttr("data.eon_host_state.web", "acknowledged", "false"),
+					resource.TestCheckResourceAttr("data.eon_host_state.db", "state", "UP"),
+					resource.TestCheckResourceAttr("data.eon_service_states.web", "services.#", "2"),
+					resource.TestCheckResourceAttr("data.eon_service_states.prod_critical", "services.#", "1"),
+					resource.TestCheckResourceAttr("data.eon_service_states.prod_critical", "services.0.host_name", "tfacc-st-web"),
+					resource.TestCheckResourceAttr("data.eon_service_states.prod_critical", "services.0.description", "HTTP"),
+					resource.TestCheckResourceAttr("data.eon_service_states.prod_critical", "services.0.output", "HTTP CRITICAL - 503"),
+				),
+			},
+			{
+				Config: `
+data "eon_host_state" "missing" {
+  name = "tfacc-st-none"
+}
+`,
+				ExpectError: regexp.MustCompile(`No monitoring state for host "tfacc-st-none"`),
+			},
+		},
+	})
+}
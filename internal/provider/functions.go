@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+//  provider::eon::check_command
+// ═══════════════════════════════════════════════════════════════════════════
+
+var _ function.Function = &checkCommandFunction{}
+
+type checkCommandFunction struct{}
+
+func NewCheckCommandFunction() function.Function { return &checkCommandFunction{} }
+
+func (f *checkCommandFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "check_command"
+}
+
+func (f *checkCommandFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds a Nagios check_command string.",
+		Description: "Joins a command name and its arguments with \"!\" ($ARG1$, $ARG2$, ...), escaping \"!\" " +
+			"inside arguments and rejecting malformed $MACRO$ references.",
+		Parameters: []function.Parameter{
+			function.StringParameter{Name: "name", Description: "Command name, e.g. \"check_http\"."},
+			function.ListParameter{Name: "args", ElementType: types.StringType, Description: "Values of $ARG1$, $ARG2$, ..."},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *checkCommandFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+	var args []string
+	resp.Error = req.Arguments.Get(ctx, &name, &args)
+	if resp.Error != nil {
+		return
+	}
+
+	if strings.TrimSpace(name) == "" || strings.ContainsAny(name, "!$") {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid command name %q", name))
+		return
+	}
+	for i, a := range args {
+		if _, err := parseMacros(a); err != nil {
+			resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("argument %d ($ARG%d$): %s", i+1, i+1, err))
+			return
+		}
+	}
+	resp.Error = resp.Result.Set(ctx, joinCheckCommand(name, args))
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+//  provider::eon::parse_command_line
+// ═══════════════════════════════════════════════════════════════════════════
+
+var _ function.Function = &parseCommandLineFunction{}
+
+var parsedCommandTypes = map[string]attr.Type{
+	"name": types.StringType,
+	"args": types.ListType{ElemType: types.StringType},
+}
+
+type parseCommandLineFunction struct{}
+
+func NewParseCommandLineFunction() function.Function { return &parseCommandLineFunction{} }
+
+func (f *parseCommandLineFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_command_line"
+}
+
+func (f *parseCommandLineFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Splits a Nagios check_command string.",
+		Description: "Returns an object with the command name and the list of its \"!\"-separated arguments, " +
+			"unescaping \"\\!\" and rejecting malformed $MACRO$ references.",
+		Parameters: []function.Parameter{
+			function.StringParameter{Name: "check_command", Description: "String such as \"check_http!80!/health\"."},
+		},
+		Return: function.ObjectReturn{AttributeTypes: parsedCommandTypes},
+	}
+}
+
+func (f *parseCommandLineFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var s string
+	resp.Error = req.Arguments.Get(ctx, &s)
+	if resp.Error != nil {
+		return
+	}
+
+	if _, err := parseMacros(s); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	name, args := splitCheckCommand(strings.TrimSpace(s))
+	if name == "" {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("%q has no command name", s))
+		return
+	}
+
+	argValues := make([]attr.Value, len(args))
+	for i, a := range args {
+		argValues[i] = types.StringValue(a)
+	}
+	resp.Error = resp.Result.Set(ctx, types.ObjectValueMust(parsedCommandTypes, map[string]attr.Value{
+		"name": types.StringValue(name),
+		"args": types.ListValueMust(types.StringType, argValues),
+	}))
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func runFunction(t *testing.T, f function.Function, ret function.Return, args ...attr.Value) (attr.Value, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+	result, ferr := ret.NewResultData(ctx)
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	resp := &function.RunResponse{Result: result}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+	return resp.Result.Value(), resp.Error
+}
+
+func stringList(vals ...string) types.List {
+	return stringListValue(vals)
+}
+
+func TestCheckCommandFunction(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{name: "check_http", args: []string{"80", "/health"}, want: "check_http!80!/health"},
+		{name: "check_ping", want: "check_ping"},
+		{name: "check_dummy", args: []string{"2", "down!"}, want: `check_dummy!2!down\!`},
+		{name: "check_cost", args: []string{"$$5"}, want: "check_cost!$$5"},
+		{name: "check_state", args: []string{"$HOSTSTATE:web01$"}, want: "check_state!$HOSTSTATE:web01$"},
+		{name: "", wantErr: "invalid command name"},
+		{name: "check_http", args: []string{"$HOSTADDRESS"}, wantErr: "unterminated macro"},
+		{name: "check_http", args: []string{"80", "$host address$"}, wantErr: "argument 2"},
+	} {
+		got, err := runFunction(t, NewCheckCommandFunction(), function.StringReturn{},
+			types.StringValue(tc.name), stringList(tc.args...))
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("check_command(%q, %q): got error %v, want %q", tc.name, tc.args, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("check_command(%q, %q): %s", tc.name, tc.args, err)
+		} else if got.(types.String).ValueString() != tc.want {
+			t.Errorf("check_command(%q, %q) = %s, want %q", tc.name, tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestParseCommandLineFunction(t *testing.T) {
+	ret := function.ObjectReturn{AttributeTypes: parsedCommandTypes}
+	for _, tc := range []struct {
+		in      string
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{in: "check_http!80!/health", name: "check_http", args: []string{"80", "/health"}},
+		{in: "check_ping", name: "check_ping", args: []string{}},
+		{in: `check_dummy!2!down\!`, name: "check_dummy", args: []string{"2", "down!"}},
+		{in: "check_http!!x", name: "check_http", args: []string{"", "x"}},
+		{in: "!80", wantErr: "no command name"},
+		{in: "check_http!$ARG1", wantErr: "unterminated macro"},
+	} {
+		got, err := runFunction(t, NewParseCommandLineFunction(), ret, types.StringValue(tc.in))
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("parse_command_line(%q): got error %v, want %q", tc.in, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parse_command_line(%q): %s", tc.in, err)
+			continue
+		}
+		want := types.ObjectValueMust(parsedCommandTypes, map[string]attr.Value{
+			"name": types.StringValue(tc.name),
+			"args": stringList(tc.args...),
+		})
+		if !got.Equal(want) {
+			t.Errorf("parse_command_line(%q) = %s, want %s", tc.in, got, want)
+		}
+	}
+}
+
+func TestAccFunctions(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks:   []tfversion.TerraformVersionCheck{tfversion.SkipBelow(tfversion.Version1_8_0)},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "eon" {}
+
+locals {
+  parsed = provider::eon::parse_command_line("check_http!80!/health")
+}
+
+output "check_command" {
+  value = provider::eon::check_command("check_http", ["80", "down!"])
+}
+
+output "name" {
+  value = local.parsed.name
+}
+
+output "second_arg" {
+  value = local.parsed.args[1]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("check_command", `check_http!80!down\!`),
+					resource.TestCheckOutput("name", "check_http"),
+					resource.TestCheckOutput("second_arg", "/health"),
+				),
+			},
+			{
+				Config: `
+provider "eon" {}
+
+output "bad" {
+  value = provider::eon::check_command("check_http", ["$HOSTADDRESS"])
+}
+`,
+				ExpectError: regexp.MustCompile(`unterminated macro`),
+			},
+		},
+	})
+}
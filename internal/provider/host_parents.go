@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"sort"
+	"sync"
+)
+
+// parentGraph records the parents of every eon_host planned since the
+// provider was configured; Configure starts a new one for every run. A host
+// cannot see the rest of the configuration, so each ModifyPlan adds its own
+// edges and looks for a cycle through the hosts planned before it: the last
+// host of a cycle reports it. Hosts not planned in this run (other
+// configurations, hosts skipped by -target) are not in the graph, so cycles
+// through them go unnoticed.
+type parentGraph struct {
+	mu      sync.Mutex
+	parents map[string][]string
+}
+
+func newParentGraph() *parentGraph {
+	return &parentGraph{parents: map[string][]string{}}
+}
+
+// plan records the parents of host and returns the parent cycle through
+// host, if any, as host, parent, ..., host.
+func (g *parentGraph) plan(host string, parents []string) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.parents[host] = append([]string(nil), parents...)
+	sort.Strings(g.parents[host])
+	return parentCycle(g.parents, host)
+}
+
+// children returns the hosts planned in this run with host as a parent.
+func (g *parentGraph) children(host string) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var out []string
+	for h, parents := range g.parents {
+		if containsString(parents, host) {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// parentCycle returns a path from host back to itself through g, or nil.
+func parentCycle(g map[string][]string, host string) []string {
+	seen := map[string]bool{}
+	var walk func(h string, path []string) []string
+	walk = func(h string, path []string) []string {
+		for _, p := range g[h] {
+			if p == host {
+				return append(path, p)
+			}
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+			if cycle := walk(p, append(path, p)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return walk(host, []string{host})
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlanParents(t *testing.T) {
+	g := newParentGraph()
+	for _, tc := range []struct {
+		host    string
+		parents []string
+		want    string
+	}{
+		{host: "sw1", parents: []string{"core"}},
+		{host: "web01", parents: []string{"sw1", "sw2"}},
+		{host: "sw2", parents: []string{"core"}},
+		{host: "core", parents: []string{}},
+		// core -> sw1 -> core once core is replanned with a parent.
+		{host: "core", parents: []string{"sw2", "sw1"}, want: "core -> sw1 -> core"},
+		{host: "core", parents: []string{"router"}},
+		{host: "router", parents: []string{"web01"}, want: "router -> web01 -> sw1 -> core -> router"},
+	} {
+		got := strings.Join(g.plan(tc.host, tc.parents), " -> ")
+		if got != tc.want {
+			t.Errorf("plan(%s, %v) = %q, want %q", tc.host, tc.parents, got, tc.want)
+		}
+	}
+
+	// A new run starts from an empty graph.
+	if cycle := newParentGraph().plan("web01", []string{"router"}); cycle != nil {
+		t.Errorf("plan on a new graph = %v, want no cycle", cycle)
+	}
+}
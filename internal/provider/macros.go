@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Nagios macros are $NAME$ references expanded when a command runs; "$$" is
+// a literal dollar sign. On-demand macros carry arguments after a colon
+// ($HOSTSTATE:web01$, $SERVICESTATE:web01:HTTP$), custom variable macros
+// start with an underscore ($_HOSTSNMP_COMMUNITY$).
+var macroNameRe = regexp.MustCompile(`^_?[A-Z][A-Z0-9_]*$`)
+
+// parseMacros returns the macro names referenced in s, without the dollar
+// signs and without on-demand arguments, in order of appearance.
+func parseMacros(s string) ([]string, error) {
+	var names []string
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' {
+			continue
+		}
+		j := strings.IndexByte(s[i+1:], '$')
+		if j < 0 {
+			return nil, fmt.Errorf("unterminated macro at %q: add the closing $, or write $$ for a literal dollar sign", s[i:])
+		}
+		macro := s[i+1 : i+1+j]
+		i += j + 1
+		if macro == "" {
+			continue
+		}
+		name, arg, onDemand := strings.Cut(macro, ":")
+		if !macroNameRe.MatchString(name) || (onDemand && (arg == "" || strings.ContainsAny(arg, "\n"))) {
+			return nil, fmt.Errorf("invalid macro $%s$: macro names are upper-case letters, digits and underscores", macro)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// joinCheckCommand builds a check_command value, escaping "!" inside
+// arguments as Nagios expects.
+func joinCheckCommand(name string, args []string) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, a := range args {
+		b.WriteByte('!')
+		b.WriteString(strings.ReplaceAll(a, "!", `\!`))
+	}
+	return b.String()
+}
+
+// splitCheckCommand is the inverse of joinCheckCommand.
+func splitCheckCommand(s string) (name string, args []string) {
+	var parts []string
+	var cur strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '!':
+			cur.WriteByte('!')
+			i++
+		case s[i] == '!':
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(s[i])
+		}
+	}
+	parts = append(parts, cur.String())
+	return parts[0], parts[1:]
+}
+
+// Nagios limits from nagios.h: MAX_COMMAND_ARGUMENTS and MAX_USER_MACROS.
+const (
+	maxArgMacro  = 32
+	maxUserMacro = 256
+)
+
+var (
+	argMacroRe  = regexp.MustCompile(`^ARG([0-9]+)$`)
+	userMacroRe = regexp.MustCompile(`^USER([0-9]+)$`)
+)
+
+// standardMacros are the Nagios 4 standard macros other than $ARGn$ and
+// $USERn$ (https://assets.nagios.com/downloads/nagioscore/docs/nagioscore/4/en/macrolist.html).
+var standardMacros = toSet(strings.Fields(`
+	HOSTNAME HOSTDISPLAYNAME HOSTALIAS HOSTADDRESS HOSTSTATE HOSTSTATEID LASTHOSTSTATE LASTHOSTSTATEID
+	HOSTSTATETYPE HOSTATTEMPT MAXHOSTATTEMPTS HOSTEVENTID LASTHOSTEVENTID HOSTPROBLEMID LASTHOSTPROBLEMID
+	HOSTLATENCY HOSTEXECUTIONTIME HOSTDURATION HOSTDURATIONSEC HOSTDOWNTIME HOSTPERCENTCHANGE
+	HOSTGROUPNAME HOSTGROUPNAMES LASTHOSTCHECK LASTHOSTSTATECHANGE LASTHOSTUP LASTHOSTDOWN
+	LASTHOSTUNREACHABLE HOSTOUTPUT LONGHOSTOUTPUT HOSTPERFDATA HOSTCHECKCOMMAND HOSTACKAUTHOR
+	HOSTACKAUTHORNAME HOSTACKAUTHORALIAS HOSTACKCOMMENT HOSTACTIONURL HOSTNOTESURL HOSTNOTES
+	TOTALHOSTSERVICES TOTALHOSTSERVICESOK TOTALHOSTSERVICESWARNING TOTALHOSTSERVICESUNKNOWN
+	TOTALHOSTSERVICESCRITICAL HOSTCHECKTYPE HOSTPARENTS HOSTCHILDREN HOSTINFOURL HOSTIMPORTANCE
+	HOSTANDSERVICESIMPORTANCE
+	HOSTGROUPALIAS HOSTGROUPMEMBERS HOSTGROUPNOTES HOSTGROUPNOTESURL HOSTGROUPACTIONURL
+	SERVICEDESC SERVICEDISPLAYNAME SERVICESTATE SERVICESTATEID LASTSERVICESTATE LASTSERVICESTATEID
+	SERVICESTATETYPE SERVICEATTEMPT MAXSERVICEATTEMPTS SERVICEISVOLATILE SERVICEEVENTID
+	LASTSERVICEEVENTID SERVICEPROBLEMID LASTSERVICEPROBLEMID SERVICELATENCY SERVICEEXECUTIONTIME
+	SERVICEDURATION SERVICEDURATIONSEC SERVICEDOWNTIME SERVICEPERCENTCHANGE SERVICEGROUPNAME
+	SERVICEGROUPNAMES LASTSERVICECHECK LASTSERVICESTATECHANGE LASTSERVICEOK LASTSERVICEWARNING
+	LASTSERVICEUNKNOWN LASTSERVICECRITICAL SERVICEOUTPUT LONGSERVICEOUTPUT SERVICEPERFDATA
+	SERVICECHECKCOMMAND SERVICEACKAUTHOR SERVICEACKAUTHORNAME SERVICEACKAUTHORALIAS SERVICEACKCOMMENT
+	SERVICEACTIONURL SERVICENOTESURL SERVICENOTES SERVICECHECKTYPE SERVICEINFOURL SERVICEIMPORTANCE
+	SERVICEGROUPALIAS SERVICEGROUPMEMBERS SERVICEGROUPNOTES SERVICEGROUPNOTESURL SERVICEGROUPACTIONURL
+	CONTACTNAME CONTACTALIAS CONTACTEMAIL CONTACTPAGER CONTACTADDRESS0 CONTACTADDRESS1 CONTACTADDRESS2
+	CONTACTADDRESS3 CONTACTADDRESS4 CONTACTADDRESS5 CONTACTGROUPNAME CONTACTGROUPNAMES
+	CONTACTGROUPALIAS CONTACTGROUPMEMBERS
+	TOTALHOSTSUP TOTALHOSTSDOWN TOTALHOSTSUNREACHABLE TOTALHOSTSDOWNUNHANDLED
+	TOTALHOSTSUNREACHABLEUNHANDLED TOTALHOSTPROBLEMS TOTALHOSTPROBLEMSUNHANDLED TOTALSERVICESOK
+	TOTALSERVICESWARNING TOTALSERVICESCRITICAL TOTALSERVICESUNKNOWN TOTALSERVICESWARNINGUNHANDLED
+	TOTALSERVICESCRITICALUNHANDLED TOTALSERVICESUNKNOWNUNHANDLED TOTALSERVICEPROBLEMS
+	TOTALSERVICEPROBLEMSUNHANDLED
+	NOTIFICATIONTYPE NOTIFICATIONRECIPIENTS NOTIFICATIONISESCALATED NOTIFICATIONAUTHOR
+	NOTIFICATIONAUTHORNAME NOTIFICATIONAUTHORALIAS NOTIFICATIONCOMMENT HOSTNOTIFICATIONNUMBER
+	HOSTNOTIFICATIONID SERVICENOTIFICATIONNUMBER SERVICENOTIFICATIONID
+	LONGDATETIME SHORTDATETIME DATE TIME TIMET ISVALIDTIME NEXTVALIDTIME
+	MAINCONFIGFILE STATUSDATAFILE COMMENTDATAFILE DOWNTIMEDATAFILE RETENTIONDATAFILE OBJECTCACHEFILE
+	TEMPFILE TEMPPATH LOGFILE RESOURCEFILE COMMANDFILE HOSTPERFDATAFILE SERVICEPERFDATAFILE
+	PROCESSSTARTTIME EVENTSTARTTIME ADMINEMAIL ADMINPAGER
+`))
+
+func toSet(list []string) map[string]bool {
+	m := make(map[string]bool, len(list))
+	for _, s := range list {
+		m[s] = true
+	}
+	return m
+}
+
+// macroIndex returns n for a $ARGn$ or $USERn$ macro name matched by re,
+// or 0 when name does not match.
+func macroIndex(re *regexp.Regexp, name string) int {
+	m := re.FindStringSubmatch(name)
+	if m == nil {
+		return 0
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return n
+}
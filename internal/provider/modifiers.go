@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// idFromName plans id as the planned name for resources whose id is their
+// name and that can be renamed in place.
+type idFromName struct{}
+
+func (idFromName) Description(context.Context) string {
+	return "The id always equals the name."
+}
+
+func (m idFromName) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (idFromName) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	var name types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.PlanValue = name
+}
+
+// refreshed returns the value EONAPI reports for an attribute, keeping the
+// state value when the response does not carry the field.
+func refreshed(cur types.String, api string) types.String {
+	if api == "" && !cur.IsNull() {
+		return cur
+	}
+	return types.StringValue(api)
+}
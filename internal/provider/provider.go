@@ -2,17 +2,25 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/ktoulliou/terraform-provider-eon/internal/client"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-var _ provider.Provider = &eonProvider{}
+var (
+	_ provider.Provider              = &eonProvider{}
+	_ provider.ProviderWithFunctions = &eonProvider{}
+)
 
 type eonProvider struct{ version string }
 
@@ -21,6 +29,14 @@ type eonProviderModel struct {
 	Username types.String `tfsdk:"username"`
 	APIKey   types.String `tfsdk:"api_key"`
 	Insecure types.Bool   `tfsdk:"insecure"`
+
+	ReadCache    types.Bool   `tfsdk:"read_cache"`
+	ReadCacheTTL types.String `tfsdk:"read_cache_ttl"`
+
+	AutoExport        types.Bool   `tfsdk:"auto_export"`
+	AutoExportJobName types.String `tfsdk:"auto_export_job_name"`
+
+	ResourceMacros types.List `tfsdk:"resource_macros"`
 }
 
 func New(version string) func() provider.Provider {
@@ -53,6 +69,31 @@ func (p *eonProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *
 				Optional:    true,
 				Description: "Skip TLS verification (default false).",
 			},
+			"read_cache": schema.BoolAttribute{
+				Optional: true,
+				Description: "Serve host, contact and command reads, and the eon_hosts, eon_contacts and " +
+					"eon_contact_groups data sources, from one listNagiosObjects snapshot per object kind " +
+					"instead of one get call per object (default false).",
+			},
+			"read_cache_ttl": schema.StringAttribute{
+				Optional:    true,
+				Description: "Lifetime of a read cache snapshot as a positive Go duration (default \"5m\").",
+			},
+			"auto_export": schema.BoolAttribute{
+				Optional: true,
+				Description: "Run a single exportConfiguration when Terraform stops the provider, " +
+					"if any object was created, modified or deleted (default false).",
+			},
+			"auto_export_job_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Job name used by auto_export (default \"terraform\").",
+			},
+			"resource_macros": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "$USERn$ macros defined in the Nagios resource file (e.g. [\"USER1\", \"USER2\"]). " +
+					"When set, eon_command rejects command lines referencing any other $USERn$.",
+			},
 		},
 	}
 }
@@ -88,6 +129,21 @@ func (p *eonProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		return
 	}
 
+	// read_cache_ttl is checked even when the cache is off, so that a bad
+	// value does not go unnoticed until someone turns read_cache on.
+	ttl := 5 * time.Minute
+	if !cfg.ReadCacheTTL.IsNull() && !cfg.ReadCacheTTL.IsUnknown() {
+		d, err := time.ParseDuration(cfg.ReadCacheTTL.ValueString())
+		if err == nil && d <= 0 {
+			err = fmt.Errorf("%q is not a positive duration", cfg.ReadCacheTTL.ValueString())
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("read_cache_ttl"), "Invalid read_cache_ttl", err.Error())
+			return
+		}
+		ttl = d
+	}
+
 	insecure := false
 	if !cfg.Insecure.IsNull() {
 		insecure = cfg.Insecure.ValueBool()
@@ -99,8 +155,51 @@ func (p *eonProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		return
 	}
 
+	if cfg.ReadCache.ValueBool() {
+		c.EnableReadCache(ttl)
+	}
+
+	data := &providerData{client: c, hostParents: newParentGraph()}
+	if !cfg.ResourceMacros.IsNull() && !cfg.ResourceMacros.IsUnknown() {
+		var macros []string
+		resp.Diagnostics.Append(cfg.ResourceMacros.ElementsAs(ctx, &macros, false)...)
+		for _, m := range macros {
+			name := strings.ToUpper(strings.Trim(m, "$"))
+			if n := macroIndex(userMacroRe, name); n < 1 || n > maxUserMacro {
+				resp.Diagnostics.AddAttributeError(path.Root("resource_macros"), "Invalid resource macro",
+					fmt.Sprintf("%q is not a $USERn$ macro (USER1 to USER%d).", m, maxUserMacro))
+				continue
+			}
+			data.resourceMacros = append(data.resourceMacros, name)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if cfg.AutoExport.ValueBool() {
+		job := "terraform"
+		if v := cfg.AutoExportJobName.ValueString(); v != "" {
+			job = v
+		}
+		registerAutoExport(c, job)
+	}
+
 	resp.DataSourceData = c
-	resp.ResourceData = c
+	resp.ResourceData = data
+}
+
+// providerData is handed to resources: the EONAPI client plus the provider
+// settings that are not part of the connection.
+type providerData struct {
+	client *client.Client
+
+	// resourceMacros lists the $USERn$ macros (as "USER1", ...) defined in
+	// the Nagios resource file. Empty means unknown: references are not checked.
+	resourceMacros []string
+
+	// hostParents collects the parents of the hosts planned in this run.
+	hostParents *parentGraph
 }
 
 func (p *eonProvider) Resources(_ context.Context) []func() resource.Resource {
@@ -109,7 +208,13 @@ func (p *eonProvider) Resources(_ context.Context) []func() resource.Resource {
 		NewCommandResource,
 		NewContactResource,
 		NewContactGroupResource,
+		NewContactGroupMembershipResource,
+		NewTimeperiodResource,
+		NewUserResource,
+		NewUserGroupResource,
 		NewExportConfigResource,
+		NewDowntimeResource,
+		NewAcknowledgementResource,
 	}
 }
 
@@ -117,5 +222,19 @@ func (p *eonProvider) DataSources(_ context.Context) []func() datasource.DataSou
 	return []func() datasource.DataSource{
 		NewHostDataSource,
 		NewCommandDataSource,
+		NewHostsDataSource,
+		NewContactDataSource,
+		NewContactsDataSource,
+		NewContactGroupDataSource,
+		NewContactGroupsDataSource,
+		NewHostStateDataSource,
+		NewServiceStatesDataSource,
+	}
+}
+
+func (p *eonProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewCheckCommandFunction,
+		NewParseCommandLineFunction,
 	}
 }
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/ktoulliou/terraform-provider-eon/internal/eontest"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// Acceptance tests run against the EON given by EON_URL, EON_USERNAME and
+// EON_API_KEY, or against an in-memory eontest.Server when EON_URL is unset.
+// Either way they only run with TF_ACC=1.
+
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"eon": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccServer is the local stand-in, or nil when testing a real EON.
+var testAccServer *eontest.Server
+
+func TestMain(m *testing.M) {
+	if os.Getenv("TF_ACC") != "" && os.Getenv("EON_URL") == "" {
+		testAccServer = eontest.NewServer()
+		os.Setenv("EON_URL", testAccServer.URL)
+		os.Setenv("EON_USERNAME", eontest.Username)
+		os.Setenv("EON_API_KEY", eontest.APIKey)
+	}
+	code := m.Run()
+	if testAccServer != nil {
+		testAccServer.Close()
+	}
+	os.Exit(code)
+}
+
+func testAccPreCheck(t *testing.T) {
+	for _, k := range []string{"EON_URL", "EON_USERNAME", "EON_API_KEY"} {
+		if os.Getenv(k) == "" {
+			t.Fatalf("%s must be set for acceptance tests", k)
+		}
+	}
+}
+
+// testAccClient returns a client for changing objects behind Terraform's
+// back in drift-injection steps.
+func testAccClient() *client.Client {
+	return client.NewClient(os.Getenv("EON_URL"), os.Getenv("EON_USERNAME"), os.Getenv("EON_API_KEY"), true)
+}
+
+// testAccLocalOnly skips tests that need to control the server's behaviour.
+func testAccLocalOnly(t *testing.T) {
+	if testAccServer == nil {
+		t.Skip("requires the local eontest server (unset EON_URL)")
+	}
+}
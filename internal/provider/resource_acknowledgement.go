@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &acknowledgementResource{}
+
+type acknowledgementResource struct{ client *client.Client }
+
+type acknowledgementModel struct {
+	ID                 types.String `tfsdk:"id"`
+	HostName           types.String `tfsdk:"host_name"`
+	ServiceDescription types.String `tfsdk:"service_description"`
+	Sticky             types.Bool   `tfsdk:"sticky"`
+	Notify             types.Bool   `tfsdk:"notify"`
+	Persistent         types.Bool   `tfsdk:"persistent"`
+	Author             types.String `tfsdk:"author"`
+	Comment            types.String `tfsdk:"comment"`
+}
+
+func NewAcknowledgementResource() resource.Resource { return &acknowledgementResource{} }
+
+func (r *acknowledgementResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_acknowledgement"
+}
+
+func (r *acknowledgementResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	replace := []planmodifier.String{stringplanmodifier.RequiresReplace()}
+	boolReplace := []planmodifier.Bool{boolplanmodifier.RequiresReplace()}
+
+	resp.Schema = schema.Schema{
+		Description: "Acknowledges the current problem of a Nagios host or service in EON. " +
+			"Destroying the resource removes the acknowledgement.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				Description:   "host_name, or host_name/service_description.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"host_name": schema.StringAttribute{
+				Required:      true,
+				Description:   "Host whose problem is acknowledged.",
+				PlanModifiers: replace,
+			},
+			"service_description": schema.StringAttribute{
+				Optional:      true,
+				Description:   "Service of host_name whose problem is acknowledged. Omit to acknowledge the host.",
+				PlanModifiers: replace,
+			},
+			"sticky": schema.BoolAttribute{
+				Optional: true, Computed: true,
+				Default:       booldefault.StaticBool(true),
+				Description:   "Keep the acknowledgement until the object recovers, across state changes (default true).",
+				PlanModifiers: boolReplace,
+			},
+			"notify": schema.BoolAttribute{
+				Optional: true, Computed: true,
+				Default:       booldefault.StaticBool(true),
+				Description:   "Send an acknowledgement notification to contacts (default true).",
+				PlanModifiers: boolReplace,
+			},
+			"persistent": schema.BoolAttribute{
+				Optional: true, Computed: true,
+				Default:       booldefault.StaticBool(false),
+				Description:   "Keep the acknowledgement comment across Nagios restarts (default false).",
+				PlanModifiers: boolReplace,
+			},
+			"author": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Description:   "Acknowledgement author. Defaults to the provider username.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown(), stringplanmodifier.RequiresReplace()},
+			},
+			"comment": schema.StringAttribute{
+				Required:      true,
+				Description:   "Acknowledgement comment.",
+				PlanModifiers: replace,
+			},
+		},
+	}
+}
+
+func (r *acknowledgementResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		r.client = req.ProviderData.(*providerData).client
+	}
+}
+
+func (r *acknowledgementResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan acknowledgementModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.Author.IsUnknown() || plan.Author.IsNull() {
+		plan.Author = types.StringValue(r.client.Username)
+	}
+	host, service := plan.HostName.ValueString(), plan.ServiceDescription.ValueString()
+
+	st, err := r.client.GetState(host, service)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading state", err.Error())
+		return
+	}
+	if st == nil {
+		resp.Diagnostics.AddError("Object not monitored",
+			fmt.Sprintf("Nagios has no state for %s; export the configuration first.", ackTarget(host, service)))
+		return
+	}
+	if st.State == 0 {
+		resp.Diagnostics.AddWarning("Nothing to acknowledge",
+			fmt.Sprintf("%s is not in a problem state; Nagios ignores the acknowledgement.", ackTarget(host, service)))
+	}
+
+	tflog.Info(ctx, "Acknowledging EON problem", map[string]interface{}{"host": host, "service": service})
+
+	_, err = r.client.Acknowledge(client.Acknowledgement{
+		HostName:           host,
+		ServiceDescription: service,
+		Sticky:             plan.Sticky.ValueBool(),
+		Notify:             plan.Notify.ValueBool(),
+		Persistent:         plan.Persistent.ValueBool(),
+		Author:             plan.Author.ValueString(),
+		Comment:            plan.Comment.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error acknowledging problem", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(ackTarget(host, service))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *acknowledgementResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state acknowledgementModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	st, err := r.client.GetState(state.HostName.ValueString(), state.ServiceDescription.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading state", err.Error())
+		return
+	}
+	// A problem that is still there but no longer acknowledged was cleared
+	// behind Terraform's back: drop it so the next apply acknowledges again.
+	// A recovered object has nothing left to acknowledge, so keep the state.
+	if st == nil || (st.State != 0 && !st.Acknowledged) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *acknowledgementResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every attribute requires replacement; nothing to do in place.
+	var plan acknowledgementModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *acknowledgementResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state acknowledgementModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	host, service := state.HostName.ValueString(), state.ServiceDescription.ValueString()
+
+	st, err := r.client.GetState(host, service)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading state", err.Error())
+		return
+	}
+	if st == nil || !st.Acknowledged {
+		return
+	}
+
+	tflog.Info(ctx, "Removing EON acknowledgement", map[string]interface{}{"host": host, "service": service})
+
+	if _, err := r.client.RemoveAcknowledgement(host, service); err != nil {
+		resp.Diagnostics.AddError("Error removing acknowledgement",
+			fmt.Sprintf("Could not remove acknowledgement of %s: %s", ackTarget(host, service), err))
+	}
+}
+
+func ackTarget(host, service string) string {
+	if service == "" {
+		return host
+	}
+	return host + "/" + service
+}
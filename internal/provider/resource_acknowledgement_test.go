@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/eontest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccAcknowledgement_basic(t *testing.T) {
+	testAccLocalOnly(t)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccCheckAcknowledged("tfacc-ack", "", false),
+			testAccCheckAcknowledged("tfacc-ack", "HTTP", false),
+		),
+		Steps: []resource.TestStep{
+			{
+				// The host is not managed here, so destroy can be checked
+				// against its state.
+				PreConfig: func() {
+					testAccServer.Put(eontest.Hosts, "tfacc-ack", eontest.Object{})
+					testAccServer.SetState("tfacc-ack", "", 1, "CRITICAL - Host Unreachable")
+					testAccServer.SetState("tfacc-ack", "HTTP", 2, "HTTP CRITICAL - 503")
+				},
+				Config: testAccAcknowledgementConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_acknowledgement.host", "id", "tfacc-ack"),
+					resource.TestCheckResourceAttr("eon_acknowledgement.host", "author", "admin"),
+					resource.TestCheckResourceAttr("eon_acknowledgement.http", "id", "tfacc-ack/HTTP"),
+					testAccCheckAcknowledged("tfacc-ack", "", true),
+					testAccCheckAcknowledged("tfacc-ack", "HTTP", true),
+				),
+			},
+			{
+				// A recovery clears the acknowledgement, but there is
+				// nothing left to acknowledge.
+				PreConfig: func() { testAccServer.SetState("tfacc-ack", "HTTP", 0, "HTTP OK") },
+				Config:    testAccAcknowledgementConfig,
+				PlanOnly:  true,
+			},
+			{
+				// The problem comes back unacknowledged.
+				PreConfig:          func() { testAccServer.SetState("tfacc-ack", "HTTP", 2, "HTTP CRITICAL - 503") },
+				Config:             testAccAcknowledgementConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccAcknowledgementConfig,
+				Check:  testAccCheckAcknowledged("tfacc-ack", "HTTP", true),
+			},
+			{
+				// Drift: the acknowledgement is removed outside Terraform.
+				PreConfig: func() {
+					if _, err := testAccClient().RemoveAcknowledgement("tfacc-ack", ""); err != nil {
+						t.Fatalf("removing acknowledgement behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccAcknowledgementConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccAcknowledgementConfig,
+				Check:  testAccCheckAcknowledged("tfacc-ack", "", true),
+			},
+		},
+	})
+}
+
+const testAccAcknowledgementConfig = `
+resource "eon_acknowledgement" "host" {
+  host_name = "tfacc-ack"
+  comment   = "Known outage"
+}
+
+resource "eon_acknowledgement" "http" {
+  host_name           = "tfacc-ack"
+  service_description = "HTTP"
+  sticky              = false
+  notify              = false
+  comment             = "Backend migration"
+}
+`
+
+func testAccCheckAcknowledged(host, service string, want bool) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		st, err := testAccClient().GetState(host, service)
+		if err != nil {
+			return err
+		}
+		if st == nil {
+			return fmt.Errorf("no state for %s", ackTarget(host, service))
+		}
+		if st.Acknowledged != want {
+			return fmt.Errorf("%s acknowledged = %t, want %t", ackTarget(host, service), st.Acknowledged, want)
+		}
+		return nil
+	}
+}
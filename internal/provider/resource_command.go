@@ -3,20 +3,29 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var _ resource.Resource = &commandResource{}
+var (
+	_ resource.Resource                = &commandResource{}
+	_ resource.ResourceWithImportState = &commandResource{}
+	_ resource.ResourceWithModifyPlan  = &commandResource{}
+)
 
-type commandResource struct{ client *client.Client }
+type commandResource struct {
+	client         *client.Client
+	resourceMacros []string
+}
 
 type commandModel struct {
 	ID          types.String `tfsdk:"id"`
@@ -37,15 +46,18 @@ func (r *commandResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:      true,
-				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+				PlanModifiers: []planmodifier.String{idFromName{}},
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Command name (e.g. check_http).",
+				Validators:  []validator.String{objectNameValidator{}},
 			},
 			"command_line": schema.StringAttribute{
-				Required:    true,
-				Description: "Full command line (e.g. $USER1$/check_http -H $HOSTADDRESS$ -p $ARG1$).",
+				Required: true,
+				Description: "Full command line (e.g. $USER1$/check_http -H $HOSTADDRESS$ -p $ARG1$). " +
+					"Macros are checked at plan time.",
+				Validators: []validator.String{commandLineValidator{}},
 			},
 			"description": schema.StringAttribute{
 				Optional: true, Computed: true,
@@ -58,7 +70,25 @@ func (r *commandResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 
 func (r *commandResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData != nil {
-		r.client = req.ProviderData.(*client.Client)
+		data := req.ProviderData.(*providerData)
+		r.client, r.resourceMacros = data.client, data.resourceMacros
+	}
+}
+
+// ModifyPlan rejects $USERn$ macros missing from the provider's
+// resource_macros; Nagios would expand them to an empty string.
+func (r *commandResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || len(r.resourceMacros) == 0 {
+		return
+	}
+	var line types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("command_line"), &line)...)
+	if line.IsNull() || line.IsUnknown() {
+		return
+	}
+	for _, name := range undefinedUserMacros(line.ValueString(), r.resourceMacros) {
+		resp.Diagnostics.AddAttributeError(path.Root("command_line"), "Undefined resource macro",
+			fmt.Sprintf("$%s$ is not in the provider's resource_macros (%s).", name, strings.Join(r.resourceMacros, ", ")))
 	}
 }
 
@@ -92,11 +122,15 @@ func (r *commandResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	_, err := r.client.GetCommand(state.Name.ValueString())
+	apiResp, err := r.client.GetCommand(state.Name.ValueString())
 	if err != nil {
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	cmd := client.DecodeCommand(client.FirstObject(apiResp))
+	state.CommandLine = refreshed(state.CommandLine, cmd.CommandLine)
+	state.Description = refreshed(state.Description, cmd.Description)
+	state.ID = state.Name
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -145,3 +179,8 @@ func (r *commandResource) Delete(ctx context.Context, req resource.DeleteRequest
 			fmt.Sprintf("Could not delete command %q: %s", state.Name.ValueString(), err))
 	}
 }
+
+func (r *commandResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
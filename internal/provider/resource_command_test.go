@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCommand_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCommandConfig("tfacc_check", "$USER1$/check_ping -H $HOSTADDRESS$", "ping"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_command.test", "id", "tfacc_check"),
+					resource.TestCheckResourceAttr("eon_command.test", "command_line", "$USER1$/check_ping -H $HOSTADDRESS$"),
+					resource.TestCheckResourceAttr("eon_command.test", "description", "ping"),
+				),
+			},
+			{
+				// Rename and change the line in place.
+				Config: testAccCommandConfig("tfacc_check2", "$USER1$/check_http -H $HOSTADDRESS$", "http"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_command.test", "id", "tfacc_check2"),
+					resource.TestCheckResourceAttr("eon_command.test", "command_line", "$USER1$/check_http -H $HOSTADDRESS$"),
+				),
+			},
+			{
+				ResourceName:      "eon_command.test",
+				ImportState:       true,
+				ImportStateId:     "tfacc_check2",
+				ImportStateVerify: true,
+			},
+			{
+				// Drift: the command line is changed outside Terraform.
+				PreConfig: func() {
+					if _, err := testAccClient().ModifyCommand("tfacc_check2", "", "$USER1$/check_dummy 0", "http"); err != nil {
+						t.Fatalf("modifying command behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccCommandConfig("tfacc_check2", "$USER1$/check_http -H $HOSTADDRESS$", "http"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccCommandConfig("tfacc_check2", "$USER1$/check_http -H $HOSTADDRESS$", "http"),
+				Check:  resource.TestCheckResourceAttr("eon_command.test", "command_line", "$USER1$/check_http -H $HOSTADDRESS$"),
+			},
+		},
+	})
+}
+
+func TestAccCommand_macros(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCommandConfig("tfacc_macro", "$USER1$/check_ping -H $HOSTADDRES$", ""),
+				ExpectError: regexp.MustCompile(`HOSTADDRES\$ is not a standard Nagios macro`),
+			},
+			{
+				Config:      testAccCommandConfig("tfacc_macro", "$USER1$/check_ping -H $HOSTADDRESS", ""),
+				ExpectError: regexp.MustCompile(`unterminated macro`),
+			},
+			{
+				Config: `
+provider "eon" {
+  resource_macros = ["USER1", "$USER2$"]
+}
+` + testAccCommandConfig("tfacc_macro", "$USER3$/check_ping -H $HOSTADDRESS$", ""),
+				ExpectError: regexp.MustCompile(`USER3\$ is not in the provider's resource_macros`),
+			},
+			{
+				Config: `
+provider "eon" {
+  resource_macros = ["USER1", "$USER2$"]
+}
+` + testAccCommandConfig("tfacc_macro", "$USER2$/check_ping -H $HOSTADDRESS$ -w $ARG2$", ""),
+				Check: resource.TestCheckResourceAttr("eon_command.test", "command_line", "$USER2$/check_ping -H $HOSTADDRESS$ -w $ARG2$"),
+			},
+		},
+	})
+}
+
+func testAccCommandConfig(name, line, desc string) string {
+	return fmt.Sprintf(`
+resource "eon_command" "test" {
+  name         = %q
+  command_line = %q
+  description  = %q
+}
+`, name, line, desc)
+}
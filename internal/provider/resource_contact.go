@@ -3,30 +3,50 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var _ resource.Resource = &contactResource{}
+var (
+	_ resource.Resource                   = &contactResource{}
+	_ resource.ResourceWithImportState    = &contactResource{}
+	_ resource.ResourceWithModifyPlan     = &contactResource{}
+	_ resource.ResourceWithUpgradeState   = &contactResource{}
+	_ resource.ResourceWithValidateConfig = &contactResource{}
+)
 
 type contactResource struct{ client *client.Client }
 
 type contactModel struct {
-	ID           types.String `tfsdk:"id"`
-	Name         types.String `tfsdk:"name"`
-	Alias        types.String `tfsdk:"alias"`
-	Mail         types.String `tfsdk:"mail"`
-	Pager        types.String `tfsdk:"pager"`
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Alias         types.String `tfsdk:"alias"`
+	Mail          types.String `tfsdk:"mail"`
+	Pager         types.String `tfsdk:"pager"`
+	ContactGroups types.Set    `tfsdk:"contact_groups"`
+	Export        types.Bool   `tfsdk:"export_configuration"`
+
+	// ContactGroup is the deprecated singular form of ContactGroups.
 	ContactGroup types.String `tfsdk:"contact_group"`
-	Export       types.Bool   `tfsdk:"export_configuration"`
+
+	HostNotificationPeriod      types.String `tfsdk:"host_notification_period"`
+	ServiceNotificationPeriod   types.String `tfsdk:"service_notification_period"`
+	HostNotificationOptions     types.Set    `tfsdk:"host_notification_options"`
+	ServiceNotificationOptions  types.Set    `tfsdk:"service_notification_options"`
+	HostNotificationCommands    types.Set    `tfsdk:"host_notification_commands"`
+	ServiceNotificationCommands types.Set    `tfsdk:"service_notification_commands"`
 }
 
 func NewContactResource() resource.Resource { return &contactResource{} }
@@ -37,15 +57,17 @@ func (r *contactResource) Metadata(_ context.Context, req resource.MetadataReque
 
 func (r *contactResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     1,
 		Description: "Manages a Nagios contact in EON (createContact / modifyContact / deleteContact).",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:      true,
-				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+				PlanModifiers: []planmodifier.String{idFromName{}},
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Contact name.",
+				Validators:  []validator.String{objectNameValidator{}},
 			},
 			"alias": schema.StringAttribute{
 				Optional: true, Computed: true,
@@ -55,45 +77,173 @@ func (r *contactResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 			"mail": schema.StringAttribute{
 				Required:    true,
 				Description: "Contact email address.",
+				Validators:  []validator.String{emailValidator{}},
 			},
 			"pager": schema.StringAttribute{
 				Optional: true, Computed: true,
 				Default:     stringdefault.StaticString(""),
 				Description: "Pager number/address.",
 			},
+			"contact_groups": schema.SetAttribute{
+				Optional: true, Computed: true,
+				ElementType: types.StringType,
+				Description: "Contact groups the contact is a member of (addContactGroupToContact / deleteContactGroupToContact). " +
+					"Omit to leave memberships unmanaged, e.g. when eon_contact_group_membership manages them.",
+				PlanModifiers: []planmodifier.Set{setplanmodifier.UseStateForUnknown()},
+				Validators:    []validator.Set{objectNamesValidator{}},
+			},
 			"contact_group": schema.StringAttribute{
-				Optional:    true,
-				Description: "Contact group to assign this contact to.",
+				Optional:           true,
+				Description:        "Single contact group the contact is a member of.",
+				DeprecationMessage: "Use contact_groups instead; contact_group will be removed in the next major release.",
+				Validators:         []validator.String{objectNameValidator{}},
 			},
 			"export_configuration": schema.BoolAttribute{
 				Optional: true, Computed: true,
 				Default:     booldefault.StaticBool(false),
 				Description: "Reload Nagios config after change.",
 			},
+			"host_notification_period": schema.StringAttribute{
+				Optional:    true,
+				Description: "Timeperiod during which host notifications are sent. Omit to inherit it from the contact template.",
+				Validators:  []validator.String{objectNameValidator{}},
+			},
+			"service_notification_period": schema.StringAttribute{
+				Optional:    true,
+				Description: "Timeperiod during which service notifications are sent. Omit to inherit it from the contact template.",
+				Validators:  []validator.String{objectNameValidator{}},
+			},
+			"host_notification_options": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Host states to notify about: d (down), u (unreachable), r (recovery), f (flapping), s (downtime), or n (none).",
+				Validators:  []validator.Set{notificationOptionsValidator{allowed: hostNotificationOptions}},
+			},
+			"service_notification_options": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Service states to notify about: w (warning), u (unknown), c (critical), r (recovery), f (flapping), s (downtime), or n (none).",
+				Validators:  []validator.Set{notificationOptionsValidator{allowed: serviceNotificationOptions}},
+			},
+			"host_notification_commands": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Commands used to notify the contact of host problems.",
+				Validators:  []validator.Set{objectNamesValidator{}},
+			},
+			"service_notification_commands": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Commands used to notify the contact of service problems.",
+				Validators:  []validator.Set{objectNamesValidator{}},
+			},
 		},
 	}
 }
 
 func (r *contactResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData != nil {
-		r.client = req.ProviderData.(*client.Client)
+		r.client = req.ProviderData.(*providerData).client
+	}
+}
+
+func (r *contactResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var group types.String
+	var groups types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("contact_group"), &group)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("contact_groups"), &groups)...)
+	if !group.IsNull() && !groups.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("contact_group"), "Conflicting attributes",
+			"contact_group cannot be set together with contact_groups.")
 	}
 }
 
-func (r *contactResource) contactBody(m *contactModel) map[string]interface{} {
+// ModifyPlan maps the deprecated contact_group onto contact_groups.
+func (r *contactResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var group types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("contact_group"), &group)...)
+	if resp.Diagnostics.HasError() || group.IsNull() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("contact_groups"), aliasSet(group))...)
+}
+
+// contactBody is the modifyContact body that takes the contact from the from
+// model to m, or the createContact body when from is empty. Notification
+// settings are only sent when set; settings no longer set are sent empty so
+// the contact inherits them again, like parametersDiff does for hosts.
+func (r *contactResource) contactBody(ctx context.Context, from, m *contactModel, export bool) map[string]interface{} {
 	body := map[string]interface{}{
 		"contactName":         m.Name.ValueString(),
 		"contactAlias":        m.Alias.ValueString(),
 		"contactMail":         m.Mail.ValueString(),
 		"contactPager":        m.Pager.ValueString(),
-		"exportConfiguration": m.Export.ValueBool(),
+		"exportConfiguration": export,
+	}
+	for k, v := range map[string][2]types.String{
+		"hostNotificationPeriod":    {from.HostNotificationPeriod, m.HostNotificationPeriod},
+		"serviceNotificationPeriod": {from.ServiceNotificationPeriod, m.ServiceNotificationPeriod},
+	} {
+		switch {
+		case !v[1].IsNull():
+			body[k] = v[1].ValueString()
+		case !v[0].IsNull():
+			body[k] = ""
+		}
 	}
-	if !m.ContactGroup.IsNull() && !m.ContactGroup.IsUnknown() {
-		body["contactGroup"] = m.ContactGroup.ValueString()
+	for k, v := range map[string][2]types.Set{
+		"hostNotificationOptions":     {from.HostNotificationOptions, m.HostNotificationOptions},
+		"serviceNotificationOptions":  {from.ServiceNotificationOptions, m.ServiceNotificationOptions},
+		"hostNotificationCommands":    {from.HostNotificationCommands, m.HostNotificationCommands},
+		"serviceNotificationCommands": {from.ServiceNotificationCommands, m.ServiceNotificationCommands},
+	} {
+		switch {
+		case !v[1].IsNull():
+			l := setStrings(ctx, v[1])
+			sort.Strings(l)
+			body[k] = strings.Join(l, ",")
+		case !v[0].IsNull():
+			body[k] = ""
+		}
 	}
 	return body
 }
 
+// converge lists the calls that bring the group memberships of the contact
+// from the from model to the to model. A null or unknown set in to is left
+// alone.
+func (r *contactResource) converge(ctx context.Context, from, to *contactModel) []linkOp {
+	name := to.Name.ValueString()
+	add, remove := setDiff(ctx, from.ContactGroups, to.ContactGroups)
+	var ops []linkOp
+	for _, g := range remove {
+		g := g
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.DeleteContactGroupToContact(g, name, e) })
+	}
+	for _, g := range add {
+		g := g
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.AddContactGroupToContact(g, name, e) })
+	}
+	return ops
+}
+
+// settle reads the contact back to fill in the contact groups left unknown
+// by the plan: EON may have added the contact to groups of its template.
+func (r *contactResource) settle(m *contactModel) error {
+	if !m.ContactGroups.IsUnknown() {
+		return nil
+	}
+	apiResp, err := r.client.GetContact(m.Name.ValueString())
+	if err != nil {
+		return err
+	}
+	m.ContactGroups = stringSetValue(client.DecodeContact(client.FirstObject(apiResp)).ContactGroups)
+	return nil
+}
+
 func (r *contactResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan contactModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -103,11 +253,21 @@ func (r *contactResource) Create(ctx context.Context, req resource.CreateRequest
 
 	tflog.Info(ctx, "Creating EON contact", map[string]interface{}{"name": plan.Name.ValueString()})
 
-	_, err := r.client.CreateContact(r.contactBody(&plan))
+	ops := r.converge(ctx, &contactModel{}, &plan)
+	export := plan.Export.ValueBool()
+	_, err := r.client.CreateContact(r.contactBody(ctx, &contactModel{}, &plan, export && len(ops) == 0))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating contact", err.Error())
 		return
 	}
+	if err := applyLinkOps(ops, export); err != nil {
+		resp.Diagnostics.AddError("Error adding contact to contact groups", err.Error())
+		return
+	}
+	if err := r.settle(&plan); err != nil {
+		resp.Diagnostics.AddError("Error reading created contact", err.Error())
+		return
+	}
 	plan.ID = plan.Name
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -118,14 +278,50 @@ func (r *contactResource) Read(ctx context.Context, req resource.ReadRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	_, err := r.client.GetContact(state.Name.ValueString())
+	apiResp, err := r.client.GetContact(state.Name.ValueString())
 	if err != nil {
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	c := client.DecodeContact(client.FirstObject(apiResp))
+	state.Alias = refreshed(state.Alias, c.Alias)
+	state.Mail = refreshed(state.Mail, c.Mail)
+	state.Pager = refreshed(state.Pager, c.Pager)
+	state.ContactGroups = refreshedSet(state.ContactGroups, c.ContactGroups)
+	state.refreshNotifications(c)
+	state.ID = state.Name
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
+// refreshNotifications refreshes the notification settings set in the
+// state. Null ones are left alone: they are inherited from the template.
+func (m *contactModel) refreshNotifications(c client.Contact) {
+	for _, p := range []struct {
+		cur *types.String
+		api string
+	}{
+		{&m.HostNotificationPeriod, c.HostNotificationPeriod},
+		{&m.ServiceNotificationPeriod, c.ServiceNotificationPeriod},
+	} {
+		if !p.cur.IsNull() {
+			*p.cur = refreshed(*p.cur, p.api)
+		}
+	}
+	for _, s := range []struct {
+		cur *types.Set
+		api []string
+	}{
+		{&m.HostNotificationOptions, c.HostNotificationOptions},
+		{&m.ServiceNotificationOptions, c.ServiceNotificationOptions},
+		{&m.HostNotificationCommands, c.HostNotificationCommands},
+		{&m.ServiceNotificationCommands, c.ServiceNotificationCommands},
+	} {
+		if !s.cur.IsNull() {
+			*s.cur = refreshedSet(*s.cur, s.api)
+		}
+	}
+}
+
 func (r *contactResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan, state contactModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -136,7 +332,9 @@ func (r *contactResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	tflog.Info(ctx, "Updating EON contact", map[string]interface{}{"name": state.Name.ValueString()})
 
-	body := r.contactBody(&plan)
+	ops := r.converge(ctx, &state, &plan)
+	export := plan.Export.ValueBool()
+	body := r.contactBody(ctx, &state, &plan, export && len(ops) == 0)
 	body["contactName"] = state.Name.ValueString()
 	if plan.Name.ValueString() != state.Name.ValueString() {
 		body["newContactName"] = plan.Name.ValueString()
@@ -147,6 +345,14 @@ func (r *contactResource) Update(ctx context.Context, req resource.UpdateRequest
 		resp.Diagnostics.AddError("Error updating contact", err.Error())
 		return
 	}
+	if err := applyLinkOps(ops, export); err != nil {
+		resp.Diagnostics.AddError("Error updating contact groups", err.Error())
+		return
+	}
+	if err := r.settle(&plan); err != nil {
+		resp.Diagnostics.AddError("Error reading updated contact", err.Error())
+		return
+	}
 	plan.ID = plan.Name
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -166,3 +372,59 @@ func (r *contactResource) Delete(ctx context.Context, req resource.DeleteRequest
 			fmt.Sprintf("Could not delete contact %q: %s", state.Name.ValueString(), err))
 	}
 }
+
+func (r *contactResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// contactModelV0 is the version 0 state: a single contact group.
+type contactModelV0 struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Alias        types.String `tfsdk:"alias"`
+	Mail         types.String `tfsdk:"mail"`
+	Pager        types.String `tfsdk:"pager"`
+	ContactGroup types.String `tfsdk:"contact_group"`
+	Export       types.Bool   `tfsdk:"export_configuration"`
+}
+
+func (r *contactResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{Attributes: map[string]schema.Attribute{
+				"id":                   schema.StringAttribute{Computed: true},
+				"name":                 schema.StringAttribute{Required: true},
+				"alias":                schema.StringAttribute{Optional: true, Computed: true},
+				"mail":                 schema.StringAttribute{Required: true},
+				"pager":                schema.StringAttribute{Optional: true, Computed: true},
+				"contact_group":        schema.StringAttribute{Optional: true},
+				"export_configuration": schema.BoolAttribute{Optional: true, Computed: true},
+			}},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var old contactModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &old)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				resp.Diagnostics.Append(resp.State.Set(ctx, contactModel{
+					ID:            old.ID,
+					Name:          old.Name,
+					Alias:         old.Alias,
+					Mail:          old.Mail,
+					Pager:         old.Pager,
+					ContactGroups: singletonSet(old.ContactGroup),
+					Export:        old.Export,
+					ContactGroup:  old.ContactGroup,
+
+					HostNotificationPeriod:      types.StringNull(),
+					ServiceNotificationPeriod:   types.StringNull(),
+					HostNotificationOptions:     types.SetNull(types.StringType),
+					ServiceNotificationOptions:  types.SetNull(types.StringType),
+					HostNotificationCommands:    types.SetNull(types.StringType),
+					ServiceNotificationCommands: types.SetNull(types.StringType),
+				})...)
+			},
+		},
+	}
+}
@@ -5,16 +5,20 @@ import (
 	"fmt"
 
 	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-var _ resource.Resource = &contactGroupResource{}
+var (
+	_ resource.Resource                = &contactGroupResource{}
+	_ resource.ResourceWithImportState = &contactGroupResource{}
+)
 
 type contactGroupResource struct{ client *client.Client }
 
@@ -37,11 +41,12 @@ func (r *contactGroupResource) Schema(_ context.Context, _ resource.SchemaReques
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:      true,
-				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+				PlanModifiers: []planmodifier.String{idFromName{}},
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Contact group name.",
+				Validators:  []validator.String{objectNameValidator{}},
 			},
 			"description": schema.StringAttribute{
 				Optional: true, Computed: true,
@@ -58,7 +63,7 @@ func (r *contactGroupResource) Schema(_ context.Context, _ resource.SchemaReques
 
 func (r *contactGroupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData != nil {
-		r.client = req.ProviderData.(*client.Client)
+		r.client = req.ProviderData.(*providerData).client
 	}
 }
 
@@ -85,11 +90,14 @@ func (r *contactGroupResource) Read(ctx context.Context, req resource.ReadReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	_, err := r.client.GetContactGroup(state.Name.ValueString())
+	apiResp, err := r.client.GetContactGroup(state.Name.ValueString())
 	if err != nil {
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	g := client.DecodeContactGroup(client.FirstObject(apiResp))
+	state.Description = refreshed(state.Description, g.Description)
+	state.ID = state.Name
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -130,3 +138,8 @@ func (r *contactGroupResource) Delete(ctx context.Context, req resource.DeleteRe
 			fmt.Sprintf("Could not delete %q: %s", state.Name.ValueString(), err))
 	}
 }
+
+func (r *contactGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
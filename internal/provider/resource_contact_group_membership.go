@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                = &contactGroupMembershipResource{}
+	_ resource.ResourceWithImportState = &contactGroupMembershipResource{}
+)
+
+// contactGroupMembershipResource adds contacts owned elsewhere (another
+// configuration, LDAP sync, the EON UI) to a contact group. Only the listed
+// contacts are managed; other members of the group are left alone.
+type contactGroupMembershipResource struct{ client *client.Client }
+
+type contactGroupMembershipModel struct {
+	ID           types.String `tfsdk:"id"`
+	ContactGroup types.String `tfsdk:"contact_group"`
+	Contacts     types.Set    `tfsdk:"contacts"`
+	Export       types.Bool   `tfsdk:"export_configuration"`
+}
+
+func NewContactGroupMembershipResource() resource.Resource {
+	return &contactGroupMembershipResource{}
+}
+
+func (r *contactGroupMembershipResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_contact_group_membership"
+}
+
+func (r *contactGroupMembershipResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages members of a Nagios contact group in EON (addContactGroupToContact / deleteContactGroupToContact). " +
+			"Members not listed here are left alone.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"contact_group": schema.StringAttribute{
+				Required:      true,
+				Description:   "Contact group name.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				Validators:    []validator.String{objectNameValidator{}},
+			},
+			"contacts": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "Contacts to add to the group.",
+				Validators:  []validator.Set{objectNamesValidator{}},
+			},
+			"export_configuration": schema.BoolAttribute{
+				Optional: true, Computed: true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Reload Nagios config after change.",
+			},
+		},
+	}
+}
+
+func (r *contactGroupMembershipResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		r.client = req.ProviderData.(*providerData).client
+	}
+}
+
+// converge lists the calls that turn the from contacts into the to contacts.
+func (r *contactGroupMembershipResource) converge(ctx context.Context, from, to types.Set, group string) []linkOp {
+	add, remove := setDiff(ctx, from, to)
+	var ops []linkOp
+	for _, c := range remove {
+		c := c
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.DeleteContactGroupToContact(group, c, e) })
+	}
+	for _, c := range add {
+		c := c
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.AddContactGroupToContact(group, c, e) })
+	}
+	return ops
+}
+
+func (r *contactGroupMembershipResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan contactGroupMembershipModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	group := plan.ContactGroup.ValueString()
+
+	tflog.Info(ctx, "Adding contacts to EON contact group", map[string]interface{}{"contact_group": group})
+
+	ops := r.converge(ctx, types.SetNull(types.StringType), plan.Contacts, group)
+	if err := applyLinkOps(ops, plan.Export.ValueBool()); err != nil {
+		resp.Diagnostics.AddError("Error adding contacts to contact group", err.Error())
+		return
+	}
+	plan.ID = plan.ContactGroup
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *contactGroupMembershipResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state contactGroupMembershipModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiResp, err := r.client.GetContactGroup(state.ContactGroup.ValueString())
+	if err != nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	// Only the managed contacts that are still members remain. A response
+	// without a members field says nothing about them and keeps the state.
+	if members := client.DecodeContactGroup(client.FirstObject(apiResp)).Members; members != nil {
+		var contacts []string
+		for _, c := range setStrings(ctx, state.Contacts) {
+			if containsString(members, c) {
+				contacts = append(contacts, c)
+			}
+		}
+		state.Contacts = stringSetValue(contacts)
+	}
+	state.ID = state.ContactGroup
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *contactGroupMembershipResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state contactGroupMembershipModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	group := plan.ContactGroup.ValueString()
+
+	tflog.Info(ctx, "Updating EON contact group members", map[string]interface{}{"contact_group": group})
+
+	ops := r.converge(ctx, state.Contacts, plan.Contacts, group)
+	if err := applyLinkOps(ops, plan.Export.ValueBool()); err != nil {
+		resp.Diagnostics.AddError("Error updating contact group members", err.Error())
+		return
+	}
+	plan.ID = plan.ContactGroup
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *contactGroupMembershipResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state contactGroupMembershipModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	group := state.ContactGroup.ValueString()
+
+	tflog.Info(ctx, "Removing contacts from EON contact group", map[string]interface{}{"contact_group": group})
+
+	if err := applyLinkOps(r.converge(ctx, state.Contacts, stringSetValue(nil), group), state.Export.ValueBool()); err != nil {
+		resp.Diagnostics.AddError("Error removing contacts from contact group",
+			fmt.Sprintf("Could not update contact group %q: %s", group, err))
+	}
+}
+
+// ImportState takes "group:contact1,contact2": the group may have members
+// managed elsewhere, so the import ID lists the ones this resource manages.
+func (r *contactGroupMembershipResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	group, list, ok := strings.Cut(req.ID, ":")
+	var contacts []string
+	for _, c := range strings.Split(list, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			contacts = append(contacts, c)
+		}
+	}
+	if !ok || group == "" || len(contacts) == 0 {
+		resp.Diagnostics.AddError("Invalid import ID",
+			fmt.Sprintf("Expected \"contact_group:contact1,contact2\", got %q.", req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), group)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("contact_group"), group)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("contacts"), stringSetValue(contacts))...)
+}
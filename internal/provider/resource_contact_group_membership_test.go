@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccContactGroupMembership_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContactGroupMembershipConfig(`[eon_contact.m1.name]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact_group_membership.test", "id", "tfacc-members"),
+					resource.TestCheckResourceAttr("eon_contact_group_membership.test", "contacts.#", "1"),
+					// The member added by eon_contact is not managed here.
+					testAccCheckContactGroupMembers("tfacc-members", "tfacc-m1", "tfacc-owner"),
+				),
+			},
+			{
+				Config: testAccContactGroupMembershipConfig(`[eon_contact.m2.name]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact_group_membership.test", "contacts.#", "1"),
+					resource.TestCheckTypeSetElemAttr("eon_contact_group_membership.test", "contacts.*", "tfacc-m2"),
+					testAccCheckContactGroupMembers("tfacc-members", "tfacc-m2", "tfacc-owner"),
+				),
+			},
+			{
+				ResourceName:  "eon_contact_group_membership.test",
+				ImportState:   true,
+				ImportStateId: "tfacc-members",
+				ExpectError:   regexp.MustCompile(`Invalid import ID`),
+			},
+			{
+				// Only the listed contacts are managed; tfacc-owner is not.
+				ResourceName:            "eon_contact_group_membership.test",
+				ImportState:             true,
+				ImportStateId:           "tfacc-members:tfacc-m2",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"export_configuration"},
+			},
+			{
+				// Drift: the contact leaves the group outside Terraform.
+				PreConfig: func() {
+					if _, err := testAccClient().DeleteContactGroupToContact("tfacc-members", "tfacc-m2", false); err != nil {
+						t.Fatalf("removing membership behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccContactGroupMembershipConfig(`[eon_contact.m2.name]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccContactGroupMembershipConfig(`[eon_contact.m1.name, eon_contact.m2.name]`),
+				Check:  testAccCheckContactGroupMembers("tfacc-members", "tfacc-m1", "tfacc-m2", "tfacc-owner"),
+			},
+			{
+				// Destroying the membership keeps the group and its other members.
+				Config: testAccContactGroupMembershipBaseConfig,
+				Check:  testAccCheckContactGroupMembers("tfacc-members", "tfacc-owner"),
+			},
+		},
+	})
+}
+
+func TestAccContactGroupMembership_membersOmitted(t *testing.T) {
+	testAccLocalOnly(t)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContactGroupMembershipConfig(`[eon_contact.m1.name]`),
+				Check:  testAccCheckContactGroupMembers("tfacc-members", "tfacc-m1", "tfacc-owner"),
+			},
+			{
+				// A group read without its members field keeps the managed contacts.
+				PreConfig: func() {
+					testAccServer.Omit("getContactGroup", "members")
+					t.Cleanup(func() { testAccServer.Omit("getContactGroup") })
+				},
+				Config:   testAccContactGroupMembershipConfig(`[eon_contact.m1.name]`),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccContactGroupMembershipBaseConfig = `
+resource "eon_contact_group" "test" {
+  name = "tfacc-members"
+}
+
+resource "eon_contact" "owner" {
+  name           = "tfacc-owner"
+  mail           = "owner@example.com"
+  contact_groups = [eon_contact_group.test.name]
+}
+
+resource "eon_contact" "m1" {
+  name = "tfacc-m1"
+  mail = "m1@example.com"
+}
+
+resource "eon_contact" "m2" {
+  name = "tfacc-m2"
+  mail = "m2@example.com"
+}
+`
+
+func testAccContactGroupMembershipConfig(contacts string) string {
+	return testAccContactGroupMembershipBaseConfig + fmt.Sprintf(`
+resource "eon_contact_group_membership" "test" {
+  contact_group = eon_contact_group.test.name
+  contacts      = %s
+}
+`, contacts)
+}
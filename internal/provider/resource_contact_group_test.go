@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccContactGroup_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContactGroupConfig("tfacc-group", "Operations"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact_group.test", "id", "tfacc-group"),
+					resource.TestCheckResourceAttr("eon_contact_group.test", "description", "Operations"),
+				),
+			},
+			{
+				// Rename in place.
+				Config: testAccContactGroupConfig("tfacc-group2", "Operations team"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact_group.test", "id", "tfacc-group2"),
+					resource.TestCheckResourceAttr("eon_contact_group.test", "description", "Operations team"),
+				),
+			},
+			{
+				ResourceName:            "eon_contact_group.test",
+				ImportState:             true,
+				ImportStateId:           "tfacc-group2",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"export_configuration"},
+			},
+			{
+				// Drift: the group is deleted outside Terraform.
+				PreConfig: func() {
+					if _, err := testAccClient().DeleteContactGroup("tfacc-group2"); err != nil {
+						t.Fatalf("deleting contact group behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccContactGroupConfig("tfacc-group2", "Operations team"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccContactGroupConfig("tfacc-group2", "Operations team"),
+				Check:  resource.TestCheckResourceAttr("eon_contact_group.test", "description", "Operations team"),
+			},
+		},
+	})
+}
+
+func testAccContactGroupConfig(name, desc string) string {
+	return fmt.Sprintf(`
+resource "eon_contact_group" "test" {
+  name        = %q
+  description = %q
+}
+`, name, desc)
+}
@@ -0,0 +1,407 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccContact_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContactConfig("tfacc-contact", "a@example.com", "Alice"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact.test", "id", "tfacc-contact"),
+					resource.TestCheckResourceAttr("eon_contact.test", "mail", "a@example.com"),
+					resource.TestCheckResourceAttr("eon_contact.test", "alias", "Alice"),
+					resource.TestCheckResourceAttr("eon_contact.test", "contact_groups.#", "1"),
+					resource.TestCheckTypeSetElemAttr("eon_contact.test", "contact_groups.*", "tfacc-contact-group"),
+				),
+			},
+			{
+				Config: testAccContactConfig("tfacc-contact", "b@example.com", "Bob"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact.test", "mail", "b@example.com"),
+					resource.TestCheckResourceAttr("eon_contact.test", "alias", "Bob"),
+				),
+			},
+			{
+				ResourceName:            "eon_contact.test",
+				ImportState:             true,
+				ImportStateId:           "tfacc-contact",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"export_configuration"},
+			},
+			{
+				// Drift: the mail address is changed outside Terraform.
+				PreConfig: func() {
+					_, err := testAccClient().ModifyContact(map[string]interface{}{
+						"contactName": "tfacc-contact", "contactMail": "mallory@example.com",
+					})
+					if err != nil {
+						t.Fatalf("modifying contact behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccContactConfig("tfacc-contact", "b@example.com", "Bob"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccContactConfig("tfacc-contact", "b@example.com", "Bob"),
+				Check:  resource.TestCheckResourceAttr("eon_contact.test", "mail", "b@example.com"),
+			},
+		},
+	})
+}
+
+func TestAccContact_groups(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContactGroupsConfig(`["tfacc-cg1"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact.test", "contact_groups.#", "1"),
+					testAccCheckContactGroupMembers("tfacc-cg1", "tfacc-contact-groups"),
+				),
+			},
+			{
+				// Moving the contact removes the old membership.
+				Config: testAccContactGroupsConfig(`["tfacc-cg2"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact.test", "contact_groups.#", "1"),
+					resource.TestCheckTypeSetElemAttr("eon_contact.test", "contact_groups.*", "tfacc-cg2"),
+					testAccCheckContactGroupMembers("tfacc-cg1"),
+					testAccCheckContactGroupMembers("tfacc-cg2", "tfacc-contact-groups"),
+				),
+			},
+			{
+				Config: testAccContactGroupsConfig(`["tfacc-cg1", "tfacc-cg2"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact.test", "contact_groups.#", "2"),
+					testAccCheckContactGroupMembers("tfacc-cg1", "tfacc-contact-groups"),
+				),
+			},
+			{
+				ResourceName:            "eon_contact.test",
+				ImportState:             true,
+				ImportStateId:           "tfacc-contact-groups",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"export_configuration"},
+			},
+			{
+				// Drift: the contact leaves a group outside Terraform.
+				PreConfig: func() {
+					if _, err := testAccClient().DeleteContactGroupToContact("tfacc-cg2", "tfacc-contact-groups", false); err != nil {
+						t.Fatalf("removing membership behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccContactGroupsConfig(`["tfacc-cg1", "tfacc-cg2"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Drift: the contact leaves its last group outside Terraform.
+				PreConfig: func() {
+					if _, err := testAccClient().DeleteContactGroupToContact("tfacc-cg1", "tfacc-contact-groups", false); err != nil {
+						t.Fatalf("removing membership behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccContactGroupsConfig(`["tfacc-cg1", "tfacc-cg2"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccContactGroupsConfig(`[]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact.test", "contact_groups.#", "0"),
+					testAccCheckContactGroupMembers("tfacc-cg1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccContact_deprecatedGroup(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccContactDeprecatedGroupConfig(`contact_group = "tfacc-cg1"` + "\n" + `contact_groups = ["tfacc-cg1"]`),
+				ExpectError: regexp.MustCompile(`Conflicting attributes`),
+			},
+			{
+				Config: testAccContactDeprecatedGroupConfig(`contact_group = "tfacc-cg1"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact.test", "contact_group", "tfacc-cg1"),
+					resource.TestCheckResourceAttr("eon_contact.test", "contact_groups.#", "1"),
+					testAccCheckContactGroupMembers("tfacc-cg1", "tfacc-contact-old"),
+				),
+			},
+			{
+				Config: testAccContactDeprecatedGroupConfig(`contact_group = "tfacc-cg2"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckContactGroupMembers("tfacc-cg1"),
+					testAccCheckContactGroupMembers("tfacc-cg2", "tfacc-contact-old"),
+				),
+			},
+			{
+				// Moving to contact_groups keeps the membership.
+				Config: testAccContactDeprecatedGroupConfig(`contact_groups = ["tfacc-cg2"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("eon_contact.test", "contact_group"),
+					testAccCheckContactGroupMembers("tfacc-cg2", "tfacc-contact-old"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccContact_notifications(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccContactNotificationsConfig("24x7", `["d", "w"]`, `["w", "c"]`),
+				ExpectError: regexp.MustCompile(`Invalid notification options`),
+			},
+			{
+				Config:      testAccContactNotificationsConfig("24x7", `["n", "d"]`, `["w", "c"]`),
+				ExpectError: regexp.MustCompile(`Invalid notification options`),
+			},
+			{
+				Config: testAccContactNotificationsConfig("24x7", `["d", "u", "r"]`, `["w", "c", "r"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact.test", "host_notification_period", "24x7"),
+					resource.TestCheckResourceAttr("eon_contact.test", "host_notification_options.#", "3"),
+					resource.TestCheckTypeSetElemAttr("eon_contact.test", "host_notification_options.*", "u"),
+					resource.TestCheckResourceAttr("eon_contact.test", "service_notification_options.#", "3"),
+					resource.TestCheckTypeSetElemAttr("eon_contact.test", "host_notification_commands.*", "notify-host-by-email"),
+				),
+			},
+			{
+				Config: testAccContactNotificationsConfig("workhours", `["n"]`, `["c"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_contact.test", "host_notification_period", "workhours"),
+					resource.TestCheckResourceAttr("eon_contact.test", "host_notification_options.#", "1"),
+					resource.TestCheckTypeSetElemAttr("eon_contact.test", "host_notification_options.*", "n"),
+					resource.TestCheckResourceAttr("eon_contact.test", "service_notification_options.#", "1"),
+				),
+			},
+			{
+				// Import cannot tell the contact's own settings from
+				// inherited ones and leaves them unset.
+				ResourceName:      "eon_contact.test",
+				ImportState:       true,
+				ImportStateId:     "tfacc-contact-notify",
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{"export_configuration",
+					"host_notification_period", "service_notification_period",
+					"host_notification_options", "service_notification_options",
+					"host_notification_commands", "service_notification_commands"},
+			},
+			{
+				// Drift: a setting is changed outside Terraform.
+				PreConfig: func() {
+					_, err := testAccClient().ModifyContact(map[string]interface{}{
+						"contactName": "tfacc-contact-notify", "hostNotificationPeriod": "24x7",
+					})
+					if err != nil {
+						t.Fatalf("modifying contact behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccContactNotificationsConfig("workhours", `["n"]`, `["c"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Settings removed from the configuration are sent empty, so
+				// the contact inherits them again.
+				Config: testAccContactConfig("tfacc-contact-notify", "notify@example.com", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("eon_contact.test", "host_notification_period"),
+					resource.TestCheckNoResourceAttr("eon_contact.test", "host_notification_options.#"),
+					testAccCheckContactNotifications("tfacc-contact-notify", ""),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckContactGroupMembers checks the members of a contact group.
+func testAccCheckContactGroupMembers(group string, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		r, err := testAccClient().GetContactGroup(group)
+		if err != nil {
+			return err
+		}
+		got := client.DecodeContactGroup(client.FirstObject(r)).Members
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			return fmt.Errorf("contact group %s members = %v, want %v", group, got, want)
+		}
+		return nil
+	}
+}
+
+// testAccCheckContactNotifications checks the host notification period and
+// options EON reports for a contact.
+func testAccCheckContactNotifications(contact, want string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		r, err := testAccClient().GetContact(contact)
+		if err != nil {
+			return err
+		}
+		c := client.DecodeContact(client.FirstObject(r))
+		got := c.HostNotificationPeriod + strings.Join(c.HostNotificationOptions, ",")
+		if got != want {
+			return fmt.Errorf("contact %s host notifications = %q, want %q", contact, got, want)
+		}
+		return nil
+	}
+}
+
+func TestContactUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		group interface{}
+		want  []string
+	}{
+		{group: "ops", want: []string{"ops"}},
+		{group: nil},
+	} {
+		var got contactModel
+		upgradeState(t, &contactResource{}, 0, map[string]tftypes.Value{
+			"id": tfString("alice"), "name": tfString("alice"), "alias": tfString(""),
+			"mail": tfString("alice@example.com"), "pager": tfString(""), "contact_group": tfString(tc.group),
+			"export_configuration": tftypes.NewValue(tftypes.Bool, false),
+		}, &got)
+		if got.Mail.ValueString() != "alice@example.com" {
+			t.Errorf("upgraded mail: got %s", got.Mail)
+		}
+		if tc.want == nil {
+			if !got.ContactGroups.IsNull() {
+				t.Errorf("empty v0 group: got contact_groups %s, want null", got.ContactGroups)
+			}
+			continue
+		}
+		if g := setStrings(ctx, got.ContactGroups); strings.Join(g, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("contact_groups = %v, want %v", g, tc.want)
+		}
+		if got.ContactGroup.ValueString() != tc.want[0] {
+			t.Errorf("deprecated contact_group = %s, want kept", got.ContactGroup)
+		}
+	}
+}
+
+func TestAccContact_validation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccContactConfig("tfacc-contact", "Ops <ops@example.com>", ""),
+				ExpectError: regexp.MustCompile(`Invalid email address`),
+			},
+			{
+				Config:      testAccContactConfig("tfacc-contact", "ops.example.com", ""),
+				ExpectError: regexp.MustCompile(`Invalid email address`),
+			},
+			{
+				Config:      testAccContactConfig("tfacc`contact", "ops@example.com", ""),
+				ExpectError: regexp.MustCompile(`Invalid Nagios object name`),
+			},
+		},
+	})
+}
+
+func testAccContactConfig(name, mail, alias string) string {
+	return fmt.Sprintf(`
+resource "eon_contact_group" "test" {
+  name = "tfacc-contact-group"
+}
+
+resource "eon_contact" "test" {
+  name           = %q
+  mail           = %q
+  alias          = %q
+  contact_groups = [eon_contact_group.test.name]
+}
+`, name, mail, alias)
+}
+
+func testAccContactGroupsConfig(groups string) string {
+	return fmt.Sprintf(`
+resource "eon_contact_group" "g1" {
+  name = "tfacc-cg1"
+}
+
+resource "eon_contact_group" "g2" {
+  name = "tfacc-cg2"
+}
+
+resource "eon_contact" "test" {
+  name           = "tfacc-contact-groups"
+  mail           = "groups@example.com"
+  contact_groups = %s
+
+  depends_on = [eon_contact_group.g1, eon_contact_group.g2]
+}
+`, groups)
+}
+
+func testAccContactDeprecatedGroupConfig(groups string) string {
+	return fmt.Sprintf(`
+resource "eon_contact_group" "g1" {
+  name = "tfacc-cg1"
+}
+
+resource "eon_contact_group" "g2" {
+  name = "tfacc-cg2"
+}
+
+resource "eon_contact" "test" {
+  name = "tfacc-contact-old"
+  mail = "old@example.com"
+  %s
+
+  depends_on = [eon_contact_group.g1, eon_contact_group.g2]
+}
+`, groups)
+}
+
+func testAccContactNotificationsConfig(period, hostOpts, serviceOpts string) string {
+	return fmt.Sprintf(`
+resource "eon_contact_group" "test" {
+  name = "tfacc-contact-group"
+}
+
+resource "eon_contact" "test" {
+  name           = "tfacc-contact-notify"
+  mail           = "notify@example.com"
+  alias          = ""
+  contact_groups = [eon_contact_group.test.name]
+
+  host_notification_period      = %[1]q
+  service_notification_period   = %[1]q
+  host_notification_options     = %[2]s
+  service_notification_options  = %[3]s
+  host_notification_commands    = ["notify-host-by-email"]
+  service_notification_commands = ["notify-service-by-email"]
+}
+`, period, hostOpts, serviceOpts)
+}
@@ -0,0 +1,335 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &downtimeResource{}
+	_ resource.ResourceWithValidateConfig = &downtimeResource{}
+)
+
+// Nagios registers a downtime asynchronously after the external command is
+// written, so the id lookup after create is retried for a short while.
+const downtimeLookupAttempts = 10
+
+type downtimeResource struct{ client *client.Client }
+
+type downtimeModel struct {
+	ID                 types.String `tfsdk:"id"`
+	HostName           types.String `tfsdk:"host_name"`
+	ServiceDescription types.String `tfsdk:"service_description"`
+	StartTime          types.String `tfsdk:"start_time"`
+	EndTime            types.String `tfsdk:"end_time"`
+	Duration           types.String `tfsdk:"duration"`
+	Fixed              types.Bool   `tfsdk:"fixed"`
+	Author             types.String `tfsdk:"author"`
+	Comment            types.String `tfsdk:"comment"`
+	Expired            types.Bool   `tfsdk:"expired"`
+}
+
+func NewDowntimeResource() resource.Resource { return &downtimeResource{} }
+
+func (r *downtimeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_downtime"
+}
+
+func (r *downtimeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	replace := []planmodifier.String{stringplanmodifier.RequiresReplace()}
+	// UseStateForUnknown only keeps a defaulted value while the downtime
+	// stays: Terraform plans a replacement from a null prior state, so the
+	// new downtime gets a window starting at its own apply.
+	computedReplace := []planmodifier.String{stringplanmodifier.UseStateForUnknown(), stringplanmodifier.RequiresReplace()}
+
+	resp.Schema = schema.Schema{
+		Description: "Schedules a Nagios host or service downtime in EON. Destroying the resource cancels the " +
+			"downtime. An expired downtime stays in state with expired = true and is not scheduled again; " +
+			"one cancelled outside Terraform before its end_time is scheduled again on next apply.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Nagios downtime id.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"host_name": schema.StringAttribute{
+				Required:      true,
+				Description:   "Host to put into downtime.",
+				PlanModifiers: replace,
+			},
+			"service_description": schema.StringAttribute{
+				Optional:      true,
+				Description:   "Service of host_name to put into downtime. Omit for a host downtime.",
+				PlanModifiers: replace,
+			},
+			"start_time": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Description: "Start of the downtime window (RFC 3339). Defaults to the time of apply, " +
+					"including the apply that replaces the downtime.",
+				PlanModifiers: computedReplace,
+			},
+			"end_time": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Description:   "End of the downtime window (RFC 3339). Defaults to start_time + duration.",
+				PlanModifiers: computedReplace,
+			},
+			"duration": schema.StringAttribute{
+				Optional:      true,
+				Description:   "Downtime length as a Go duration (e.g. \"2h\"). Required for flexible downtimes.",
+				PlanModifiers: replace,
+			},
+			"fixed": schema.BoolAttribute{
+				Optional: true, Computed: true,
+				Default:       booldefault.StaticBool(true),
+				Description:   "Fixed downtime (default true). A flexible downtime starts with the first problem inside the window and lasts duration.",
+				PlanModifiers: []planmodifier.Bool{boolplanmodifier.RequiresReplace()},
+			},
+			"author": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Description:   "Downtime author. Defaults to the provider username.",
+				PlanModifiers: computedReplace,
+			},
+			"comment": schema.StringAttribute{
+				Required:      true,
+				Description:   "Downtime comment.",
+				PlanModifiers: replace,
+			},
+			"expired": schema.BoolAttribute{
+				Computed:      true,
+				Description:   "True once end_time has passed and Nagios no longer lists the downtime.",
+				PlanModifiers: []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
+			},
+		},
+	}
+}
+
+func (r *downtimeResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var cfg downtimeModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, attr := range []struct {
+		name string
+		val  types.String
+	}{{"start_time", cfg.StartTime}, {"end_time", cfg.EndTime}} {
+		if attr.val.IsNull() || attr.val.IsUnknown() {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, attr.val.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root(attr.name), "Invalid time", err.Error())
+		}
+	}
+	if !cfg.Duration.IsNull() && !cfg.Duration.IsUnknown() {
+		if d, err := time.ParseDuration(cfg.Duration.ValueString()); err != nil || d <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("duration"), "Invalid duration",
+				fmt.Sprintf("%q is not a positive Go duration.", cfg.Duration.ValueString()))
+		}
+	}
+
+	if cfg.Duration.IsNull() && cfg.EndTime.IsNull() {
+		resp.Diagnostics.AddError("Missing downtime window", "Set end_time, duration, or both.")
+	}
+	if !cfg.Fixed.IsNull() && !cfg.Fixed.ValueBool() && cfg.Duration.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("duration"), "Missing duration",
+			"Flexible downtimes (fixed = false) need a duration.")
+	}
+}
+
+func (r *downtimeResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		r.client = req.ProviderData.(*providerData).client
+	}
+}
+
+// downtime converts the plan into a client.Downtime, filling start, end and
+// author defaults.
+func (r *downtimeResource) downtime(m *downtimeModel) (client.Downtime, error) {
+	d := client.Downtime{
+		HostName:           m.HostName.ValueString(),
+		ServiceDescription: m.ServiceDescription.ValueString(),
+		Fixed:              m.Fixed.ValueBool(),
+		Author:             r.client.Username,
+		Comment:            m.Comment.ValueString(),
+	}
+	if !m.Author.IsNull() && !m.Author.IsUnknown() {
+		d.Author = m.Author.ValueString()
+	}
+
+	start := time.Now().Truncate(time.Second)
+	if !m.StartTime.IsNull() && !m.StartTime.IsUnknown() {
+		t, err := time.Parse(time.RFC3339, m.StartTime.ValueString())
+		if err != nil {
+			return d, err
+		}
+		start = t
+	}
+	var dur time.Duration
+	if !m.Duration.IsNull() {
+		v, err := time.ParseDuration(m.Duration.ValueString())
+		if err != nil {
+			return d, err
+		}
+		dur = v
+	}
+	end := start.Add(dur)
+	if !m.EndTime.IsNull() && !m.EndTime.IsUnknown() {
+		t, err := time.Parse(time.RFC3339, m.EndTime.ValueString())
+		if err != nil {
+			return d, err
+		}
+		end = t
+	}
+	if !end.After(start) {
+		return d, fmt.Errorf("end_time %s is not after start_time %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+
+	d.StartTime = start.Unix()
+	d.EndTime = end.Unix()
+	d.Duration = int64(dur / time.Second)
+	if d.Fixed {
+		d.Duration = d.EndTime - d.StartTime
+	}
+	return d, nil
+}
+
+func (r *downtimeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan downtimeModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d, err := r.downtime(&plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid downtime", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "Scheduling EON downtime", map[string]interface{}{
+		"host": d.HostName, "service": d.ServiceDescription,
+	})
+
+	if _, err := r.client.CreateDowntime(d); err != nil {
+		resp.Diagnostics.AddError("Error creating downtime", err.Error())
+		return
+	}
+
+	var found *client.Downtime
+	for i := 0; i < downtimeLookupAttempts && found == nil; i++ {
+		if i > 0 {
+			time.Sleep(time.Second)
+		}
+		found, err = r.client.FindDowntime(d)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading downtime", err.Error())
+			return
+		}
+	}
+	if found == nil {
+		resp.Diagnostics.AddError("Downtime not registered",
+			fmt.Sprintf("Nagios did not list the downtime for %q after scheduling it.", d.HostName))
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.FormatInt(found.ID, 10))
+	if plan.StartTime.IsUnknown() {
+		plan.StartTime = types.StringValue(time.Unix(d.StartTime, 0).UTC().Format(time.RFC3339))
+	}
+	if plan.EndTime.IsUnknown() {
+		plan.EndTime = types.StringValue(time.Unix(d.EndTime, 0).UTC().Format(time.RFC3339))
+	}
+	if plan.Author.IsUnknown() {
+		plan.Author = types.StringValue(d.Author)
+	}
+	plan.Expired = types.BoolValue(false)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *downtimeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state downtimeModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Expired.ValueBool() {
+		return
+	}
+	id, err := strconv.ParseInt(state.ID.ValueString(), 10, 64)
+	if err != nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	d, err := r.client.GetDowntime(state.HostName.ValueString(), id)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading downtime", err.Error())
+		return
+	}
+	if d == nil {
+		// Nagios drops a downtime when its window closes. Keep it in state
+		// so the next apply does not schedule it again from now; one that
+		// is gone before its end was cancelled outside Terraform.
+		end, err := time.Parse(time.RFC3339, state.EndTime.ValueString())
+		if err != nil || time.Now().Before(end) {
+			tflog.Info(ctx, "EON downtime was cancelled", map[string]interface{}{"id": id})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		tflog.Info(ctx, "EON downtime has expired", map[string]interface{}{"id": id})
+		state.Expired = types.BoolValue(true)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+	state.Expired = types.BoolValue(false)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *downtimeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every attribute requires replacement; nothing to do in place.
+	var plan downtimeModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *downtimeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state downtimeModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := strconv.ParseInt(state.ID.ValueString(), 10, 64)
+	if err != nil || state.Expired.ValueBool() {
+		return
+	}
+	d, err := r.client.GetDowntime(state.HostName.ValueString(), id)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading downtime", err.Error())
+		return
+	}
+	if d == nil {
+		return
+	}
+
+	tflog.Info(ctx, "Cancelling EON downtime", map[string]interface{}{"id": id})
+
+	if _, err := r.client.DeleteDowntime(*d); err != nil {
+		resp.Diagnostics.AddError("Error deleting downtime",
+			fmt.Sprintf("Could not cancel downtime %d on %q: %s", id, d.HostName, err))
+	}
+}
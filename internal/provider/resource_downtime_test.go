@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccDowntime_basic(t *testing.T) {
+	testAccLocalOnly(t)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDowntimesGone("tfacc-dt"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "eon_downtime" "test" {
+  host_name = "tfacc-dt"
+  comment   = "no window"
+}
+`,
+				ExpectError: regexp.MustCompile(`Missing downtime window`),
+			},
+			{
+				PreConfig: func() { testAccServer.SetState("tfacc-dt", "PING", 0, "PING OK") },
+				Config:    testAccDowntimeConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("eon_downtime.patching", "id"),
+					resource.TestCheckResourceAttrSet("eon_downtime.patching", "start_time"),
+					resource.TestCheckResourceAttrSet("eon_downtime.patching", "end_time"),
+					resource.TestCheckResourceAttr("eon_downtime.patching", "author", "admin"),
+					resource.TestCheckResourceAttr("eon_downtime.patching", "expired", "false"),
+					resource.TestCheckResourceAttr("eon_downtime.ping", "service_description", "PING"),
+					resource.TestCheckResourceAttr("eon_downtime.past", "expired", "false"),
+					resource.TestCheckResourceAttr("data.eon_host_state.test", "in_downtime", "true"),
+				),
+			},
+			{
+				// Drift: the downtime is cancelled outside Terraform.
+				PreConfig: func() {
+					c := testAccClient()
+					all, err := c.ListDowntimes("tfacc-dt")
+					if err != nil {
+						t.Fatalf("listing downtimes: %s", err)
+					}
+					for _, d := range all {
+						if d.Comment == "Kernel patching" {
+							if _, err := c.DeleteDowntime(d); err != nil {
+								t.Fatalf("cancelling downtime behind Terraform's back: %s", err)
+							}
+						}
+					}
+				},
+				Config:             testAccDowntimeConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccDowntimeConfig,
+				Check:  testAccCheckDowntimeCount("tfacc-dt", 3),
+			},
+			{
+				// The window of eon_downtime.past closes: it stays in state
+				// and is not scheduled again.
+				PreConfig: func() { testAccServer.ExpireDowntimes() },
+				Config:    testAccDowntimeConfig,
+				PlanOnly:  true,
+			},
+			{
+				Config: testAccDowntimeConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_downtime.past", "expired", "true"),
+					resource.TestCheckResourceAttr("eon_downtime.patching", "expired", "false"),
+					testAccCheckDowntimeCount("tfacc-dt", 2),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDowntime_replaceDefaultWindow(t *testing.T) {
+	testAccLocalOnly(t)
+	var window [2]string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDowntimesGone("tfacc-dt-replace"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDowntimeReplaceConfig("First pass"),
+				Check: func(s *terraform.State) error {
+					attrs := s.RootModule().Resources["eon_downtime.test"].Primary.Attributes
+					window = [2]string{attrs["start_time"], attrs["end_time"]}
+					return nil
+				},
+			},
+			{
+				// Replaced for its comment, the downtime starts again at
+				// apply time instead of reusing the old window.
+				PreConfig: func() { time.Sleep(time.Second) },
+				Config:    testAccDowntimeReplaceConfig("Second pass"),
+				Check: func(s *terraform.State) error {
+					attrs := s.RootModule().Resources["eon_downtime.test"].Primary.Attributes
+					if attrs["start_time"] == window[0] || attrs["end_time"] == window[1] {
+						return fmt.Errorf("window still %s - %s after replacement", attrs["start_time"], attrs["end_time"])
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccDowntimeReplaceConfig(comment string) string {
+	return fmt.Sprintf(`
+resource "eon_host" "test" {
+  name  = "tfacc-dt-replace"
+  ip    = "10.97.0.2"
+  alias = "Downtime host"
+}
+
+resource "eon_downtime" "test" {
+  host_name = eon_host.test.name
+  duration  = "2h"
+  comment   = %q
+}
+`, comment)
+}
+
+const testAccDowntimeConfig = `
+resource "eon_host" "test" {
+  name  = "tfacc-dt"
+  ip    = "10.97.0.1"
+  alias = "Downtime host"
+}
+
+resource "eon_downtime" "patching" {
+  host_name = eon_host.test.name
+  duration  = "2h"
+  comment   = "Kernel patching"
+}
+
+resource "eon_downtime" "ping" {
+  host_name           = eon_host.test.name
+  service_description = "PING"
+  duration            = "30m"
+  fixed               = false
+  comment             = "Network work"
+}
+
+resource "eon_downtime" "past" {
+  host_name  = eon_host.test.name
+  start_time = "2020-01-01T00:00:00Z"
+  end_time   = "2020-01-01T01:00:00Z"
+  comment    = "Old window"
+}
+
+data "eon_host_state" "test" {
+  name       = eon_host.test.name
+  depends_on = [eon_downtime.patching]
+}
+`
+
+func testAccCheckDowntimeCount(host string, want int) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		all, err := testAccClient().ListDowntimes(host)
+		if err != nil {
+			return err
+		}
+		if len(all) != want {
+			return fmt.Errorf("%s has %d downtimes, want %d", host, len(all), want)
+		}
+		return nil
+	}
+}
+
+func testAccCheckDowntimesGone(host string) resource.TestCheckFunc {
+	return testAccCheckDowntimeCount(host, 0)
+}
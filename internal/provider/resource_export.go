@@ -2,25 +2,39 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var _ resource.Resource = &exportConfigResource{}
+var (
+	_ resource.Resource                   = &exportConfigResource{}
+	_ resource.ResourceWithValidateConfig = &exportConfigResource{}
+)
+
+// exportPollInterval is how often the export job status is polled.
+const exportPollInterval = 2 * time.Second
 
 type exportConfigResource struct{ client *client.Client }
 
 type exportConfigModel struct {
-	ID      types.String `tfsdk:"id"`
-	JobName types.String `tfsdk:"job_name"`
+	ID             types.String `tfsdk:"id"`
+	JobName        types.String `tfsdk:"job_name"`
+	WaitTimeout    types.String `tfsdk:"wait_timeout"`
+	Triggers       types.Map    `tfsdk:"triggers"`
+	LastExportedAt types.String `tfsdk:"last_exported_at"`
 }
 
 func NewExportConfigResource() resource.Resource { return &exportConfigResource{} }
@@ -42,13 +56,42 @@ func (r *exportConfigResource) Schema(_ context.Context, _ resource.SchemaReques
 				Required:    true,
 				Description: "Export job name (arbitrary label, e.g. 'terraform').",
 			},
+			"wait_timeout": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Default: stringdefault.StaticString("10m"),
+				Description: "How long to wait for the export job to finish, as a Go duration (default \"10m\"). " +
+					"Nagios verify errors in the job output fail the apply; warnings are reported. Changing it alone does not export.",
+			},
+			"triggers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values that force a new export when they change, e.g. hashes of the " +
+					"names of the hosts, commands and contacts being managed.",
+				PlanModifiers: []planmodifier.Map{mapplanmodifier.RequiresReplace()},
+			},
+			"last_exported_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Time of the last successful export (RFC 3339).",
+			},
 		},
 	}
 }
 
+func (r *exportConfigResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var cfg exportConfigModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() || cfg.WaitTimeout.IsNull() || cfg.WaitTimeout.IsUnknown() {
+		return
+	}
+	if d, err := time.ParseDuration(cfg.WaitTimeout.ValueString()); err != nil || d <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("wait_timeout"), "Invalid wait_timeout",
+			fmt.Sprintf("%q is not a positive Go duration.", cfg.WaitTimeout.ValueString()))
+	}
+}
+
 func (r *exportConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData != nil {
-		r.client = req.ProviderData.(*client.Client)
+		r.client = req.ProviderData.(*providerData).client
 	}
 }
 
@@ -61,15 +104,87 @@ func (r *exportConfigResource) Create(ctx context.Context, req resource.CreateRe
 
 	tflog.Info(ctx, "Exporting Nagios configuration")
 
-	_, err := r.client.ExportConfiguration(plan.JobName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Error exporting configuration", err.Error())
+	resp.Diagnostics.Append(r.export(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	plan.ID = types.StringValue(fmt.Sprintf("export-%d", time.Now().Unix()))
+	now := time.Now()
+	plan.ID = types.StringValue(fmt.Sprintf("export-%d", now.Unix()))
+	plan.LastExportedAt = types.StringValue(now.UTC().Format(time.RFC3339))
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// export runs exportConfiguration and waits for the job to finish, turning
+// the Nagios verify output into diagnostics.
+func (r *exportConfigResource) export(ctx context.Context, m *exportConfigModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	timeout, err := time.ParseDuration(m.WaitTimeout.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("wait_timeout"), "Invalid wait_timeout", err.Error())
+		return diags
+	}
+	job := m.JobName.ValueString()
+
+	// Remember the previous run of the job name so that its status is not
+	// taken for the one of the export triggered below. A job that never ran
+	// is not found, which leaves nothing to confuse it with.
+	prev, err := r.client.GetExportJob(job)
+	if err != nil && !errors.Is(err, client.ErrNotFound) {
+		diags.AddError("Error reading export job", err.Error())
+		return diags
+	}
+
+	if _, err := r.client.ExportConfiguration(job); err != nil {
+		diags.AddError("Error exporting configuration", err.Error())
+		return diags
+	}
+
+	deadline := time.Now().Add(timeout)
+	var status *client.ExportJob
+	for {
+		status, err = r.client.GetExportJob(job)
+		if err != nil {
+			diags.AddError("Error reading export job", err.Error())
+			return diags
+		}
+		started := status.Supersedes(prev)
+		if started && status.Done() {
+			break
+		}
+		if time.Now().After(deadline) {
+			if !started {
+				diags.AddError("Export timed out",
+					fmt.Sprintf("Export job %q did not start within %s.", job, timeout))
+				return diags
+			}
+			diags.AddError("Export timed out",
+				fmt.Sprintf("Export job %q still %q after %s.", job, status.Status, timeout))
+			return diags
+		}
+		tflog.Debug(ctx, "Waiting for export job", map[string]interface{}{"job": job, "status": status.Status})
+		select {
+		case <-ctx.Done():
+			diags.AddError("Export cancelled", ctx.Err().Error())
+			return diags
+		case <-time.After(exportPollInterval):
+		}
+	}
+
+	errs, warnings := client.ParseVerifyOutput(status.Output)
+	for _, w := range warnings {
+		diags.AddWarning("Nagios configuration warning", w)
+	}
+	for _, e := range errs {
+		diags.AddError("Nagios configuration error", e)
+	}
+	if status.Failed() && len(errs) == 0 {
+		diags.AddError("Export job failed",
+			fmt.Sprintf("Export job %q ended with status %q:\n%s", job, status.Status, status.Output))
+	}
+	return diags
+}
+
 func (r *exportConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state exportConfigModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -77,17 +192,25 @@ func (r *exportConfigResource) Read(ctx context.Context, req resource.ReadReques
 }
 
 func (r *exportConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan exportConfigModel
+	var plan, state exportConfigModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	_, err := r.client.ExportConfiguration(plan.JobName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Error exporting configuration", err.Error())
+	// wait_timeout only matters to the next export: it alone starts none.
+	if plan.JobName.Equal(state.JobName) {
+		state.WaitTimeout = plan.WaitTimeout
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+	resp.Diagnostics.Append(r.export(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	plan.ID = types.StringValue(fmt.Sprintf("export-%d", time.Now().Unix()))
+	// id is planned from state; only last_exported_at moves on update.
+	plan.ID = state.ID
+	plan.LastExportedAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
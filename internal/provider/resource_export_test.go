@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccExportConfiguration_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExportConfig("tfacc", "a"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("eon_export_configuration.test", "id"),
+					resource.TestCheckResourceAttrSet("eon_export_configuration.test", "last_exported_at"),
+					resource.TestCheckResourceAttr("eon_export_configuration.test", "triggers.hosts", "a"),
+				),
+			},
+			{
+				// A trigger change replaces the resource, i.e. exports again.
+				Config: testAccExportConfig("tfacc", "b"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_export_configuration.test", "triggers.hosts", "b"),
+					testAccCheckExportCount(2),
+				),
+			},
+			{
+				// Unchanged triggers: no new export.
+				Config: testAccExportConfig("tfacc", "b"),
+				Check:  testAccCheckExportCount(2),
+			},
+			{
+				// A job_name change updates in place and exports again.
+				Config: testAccExportConfig("tfacc-renamed", "b"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_export_configuration.test", "job_name", "tfacc-renamed"),
+					resource.TestCheckResourceAttrSet("eon_export_configuration.test", "last_exported_at"),
+					testAccCheckExportCount(3),
+				),
+			},
+			{
+				// A wait_timeout change is saved without exporting.
+				Config: `
+resource "eon_export_configuration" "test" {
+  job_name     = "tfacc-renamed"
+  wait_timeout = "5m"
+  triggers = {
+    hosts = "b"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_export_configuration.test", "wait_timeout", "5m"),
+					testAccCheckExportCount(3),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckExportCount checks how many exports the local server ran since
+// the test started; it is a no-op against a real EON.
+func testAccCheckExportCount(want int) resource.TestCheckFunc {
+	if testAccServer == nil {
+		return func(*terraform.State) error { return nil }
+	}
+	base := testAccServer.Calls("exportConfiguration")
+	return func(*terraform.State) error {
+		if got := testAccServer.Calls("exportConfiguration") - base; got != want {
+			return fmt.Errorf("exportConfiguration called %d times, want %d", got, want)
+		}
+		return nil
+	}
+}
+
+func TestAccExportConfiguration_verifyErrors(t *testing.T) {
+	testAccLocalOnly(t)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					saved := testAccServer.ExportOutput
+					t.Cleanup(func() { testAccServer.ExportOutput = saved })
+					testAccServer.ExportOutput = "Error: Service check command 'check_x' specified in service 'PING' for host 'web01' not defined anywhere!\n"
+				},
+				Config:      testAccExportConfig("tfacc-broken", "a"),
+				ExpectError: regexp.MustCompile(`check_x`),
+			},
+		},
+	})
+}
+
+// TestAccExportConfiguration_previousRun checks that the status of the
+// previous run of a job name is not taken for the one of a new export.
+func TestAccExportConfiguration_previousRun(t *testing.T) {
+	testAccLocalOnly(t)
+	var exports int
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "eon_export_configuration" "test" {
+  job_name     = "tfacc-rerun"
+  wait_timeout = "soon"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid wait_timeout`),
+			},
+			{
+				PreConfig: func() {
+					t.Cleanup(func() { testAccServer.ExportFailed, testAccServer.ExportQueued = false, 0 })
+					testAccServer.ExportFailed = true
+					if _, err := testAccClient().ExportConfiguration("tfacc-rerun"); err != nil {
+						t.Fatalf("running a failed export: %s", err)
+					}
+					if _, err := testAccClient().GetExportJob("tfacc-rerun"); err != nil {
+						t.Fatalf("reading the failed export: %s", err)
+					}
+					testAccServer.ExportFailed, testAccServer.ExportQueued = false, 1
+				},
+				Config: testAccExportConfig("tfacc-rerun", "a"),
+				Check:  resource.TestCheckResourceAttrSet("eon_export_configuration.test", "last_exported_at"),
+			},
+			{
+				// Failing to read the previous run is not mistaken for a job
+				// that never ran: the export is not started at all.
+				PreConfig: func() {
+					testAccServer.Fail("getExportJob", 1)
+					exports = testAccServer.Calls("exportConfiguration")
+				},
+				Config:      testAccExportConfig("tfacc-rerun", "b"),
+				ExpectError: regexp.MustCompile(`Error reading export job`),
+			},
+			{
+				PreConfig: func() {
+					if n := testAccServer.Calls("exportConfiguration"); n != exports {
+						t.Fatalf("exportConfiguration called %d times after the failed read", n-exports)
+					}
+				},
+				Config: testAccExportConfig("tfacc-rerun", "b"),
+				Check:  resource.TestCheckResourceAttrSet("eon_export_configuration.test", "last_exported_at"),
+			},
+		},
+	})
+}
+
+func testAccExportConfig(job, trigger string) string {
+	return fmt.Sprintf(`
+resource "eon_export_configuration" "test" {
+  job_name = %q
+  triggers = {
+    hosts = %q
+  }
+}
+`, job, trigger)
+}
@@ -2,36 +2,70 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
-	_ resource.Resource                = &hostResource{}
-	_ resource.ResourceWithImportState = &hostResource{}
+	_ resource.Resource                   = &hostResource{}
+	_ resource.ResourceWithImportState    = &hostResource{}
+	_ resource.ResourceWithModifyPlan     = &hostResource{}
+	_ resource.ResourceWithUpgradeState   = &hostResource{}
+	_ resource.ResourceWithValidateConfig = &hostResource{}
 )
 
-type hostResource struct{ client *client.Client }
+type hostResource struct {
+	client  *client.Client
+	parents *parentGraph
+}
 
 type hostModel struct {
-	ID           types.String `tfsdk:"id"`
-	Name         types.String `tfsdk:"name"`
-	IP           types.String `tfsdk:"ip"`
-	Alias        types.String `tfsdk:"alias"`
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	IP            types.String `tfsdk:"ip"`
+	Alias         types.String `tfsdk:"alias"`
+	Templates     types.List   `tfsdk:"templates"`
+	Contacts      types.Set    `tfsdk:"contacts"`
+	ContactGroups types.Set    `tfsdk:"contact_groups"`
+	Parents       types.Set    `tfsdk:"parents"`
+	Export        types.Bool   `tfsdk:"export_configuration"`
+
+	// Deprecated singular forms of templates, contacts and contact_groups.
 	Template     types.String `tfsdk:"template"`
 	Contact      types.String `tfsdk:"contact"`
 	ContactGroup types.String `tfsdk:"contact_group"`
-	Export       types.Bool   `tfsdk:"export_configuration"`
+
+	CustomVariables          types.Map `tfsdk:"custom_variables"`
+	SensitiveCustomVariables types.Map `tfsdk:"sensitive_custom_variables"`
+
+	// Check and notification directives; null inherits the templates' value.
+	MaxCheckAttempts     types.Int64  `tfsdk:"max_check_attempts"`
+	CheckInterval        types.Int64  `tfsdk:"check_interval"`
+	RetryInterval        types.Int64  `tfsdk:"retry_interval"`
+	ActiveChecks         types.Bool   `tfsdk:"active_checks_enabled"`
+	PassiveChecks        types.Bool   `tfsdk:"passive_checks_enabled"`
+	NotificationInterval types.Int64  `tfsdk:"notification_interval"`
+	NotificationPeriod   types.String `tfsdk:"notification_period"`
+	FlapDetection        types.Bool   `tfsdk:"flap_detection_enabled"`
 }
 
 func NewHostResource() resource.Resource { return &hostResource{} }
@@ -42,69 +76,481 @@ func (r *hostResource) Metadata(_ context.Context, req resource.MetadataRequest,
 
 func (r *hostResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages a Nagios host in EON (createHost / deleteHost).",
+		Version:     2,
+		Description: "Manages a Nagios host in EON (createHost / deleteHost, templates and contacts linked in place).",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				Computed: true,
+				Computed:      true,
 				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"name": schema.StringAttribute{
-				Required:    true,
-				Description: "Nagios host name (unique identifier).",
+				Required:      true,
+				Description:   "Nagios host name (unique identifier).",
 				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				Validators:    []validator.String{objectNameValidator{}},
 			},
 			"ip": schema.StringAttribute{
-				Required:    true,
-				Description: "Host IP address or FQDN.",
+				Required: true,
+				Description: "Host IP address or FQDN. Changing it deletes and recreates the host in EON: links from " +
+					"child hosts are restored, but settings made outside Terraform are lost.",
+				Validators: []validator.String{addressValidator{}},
 			},
 			"alias": schema.StringAttribute{
 				Optional: true, Computed: true,
 				Default:     stringdefault.StaticString(""),
-				Description: "Host alias / description.",
+				Description: "Host alias / description. Changing it recreates the host like ip does.",
+			},
+			"templates": schema.ListAttribute{
+				Optional: true, Computed: true,
+				ElementType: types.StringType,
+				Default: listdefault.StaticValue(types.ListValueMust(types.StringType,
+					[]attr.Value{types.StringValue("GENERIC_HOST")})),
+				Description: "Host templates in inheritance order, first one wins (default: [\"GENERIC_HOST\"]). " +
+					"Changed in place with addHostTemplateToHost / deleteHostTemplateToHost.",
+				Validators: []validator.List{objectNamesValidator{}},
+			},
+			"contacts": schema.SetAttribute{
+				Optional: true, Computed: true,
+				ElementType: types.StringType,
+				Description: "Nagios contacts attached to the host (addContactToHost / deleteContactToHost). " +
+					"Omit to leave the host's contacts unmanaged.",
+				PlanModifiers: []planmodifier.Set{setplanmodifier.UseStateForUnknown()},
+				Validators:    []validator.Set{objectNamesValidator{}},
+			},
+			"contact_groups": schema.SetAttribute{
+				Optional: true, Computed: true,
+				ElementType: types.StringType,
+				Description: "Nagios contact groups attached to the host (addContactGroupToHost / deleteContactGroupToHost). " +
+					"Omit to leave the host's contact groups unmanaged.",
+				PlanModifiers: []planmodifier.Set{setplanmodifier.UseStateForUnknown()},
+				Validators:    []validator.Set{objectNamesValidator{}},
 			},
 			"template": schema.StringAttribute{
 				Optional: true, Computed: true,
-				Default:     stringdefault.StaticString("GENERIC_HOST"),
-				Description: "Parent host template (default: GENERIC_HOST).",
+				Description:        "Single host template; reads back as the first of templates.",
+				DeprecationMessage: "Use templates instead; template will be removed in the next major release.",
+				Validators:         []validator.String{objectNameValidator{}},
 			},
 			"contact": schema.StringAttribute{
-				Optional:    true,
-				Description: "Nagios contact to attach.",
+				Optional:           true,
+				Description:        "Single Nagios contact attached to the host.",
+				DeprecationMessage: "Use contacts instead; contact will be removed in the next major release.",
+				Validators:         []validator.String{objectNameValidator{}},
 			},
 			"contact_group": schema.StringAttribute{
-				Optional:    true,
-				Description: "Nagios contact group to attach.",
+				Optional:           true,
+				Description:        "Single Nagios contact group attached to the host.",
+				DeprecationMessage: "Use contact_groups instead; contact_group will be removed in the next major release.",
+				Validators:         []validator.String{objectNameValidator{}},
+			},
+			"parents": schema.SetAttribute{
+				Optional: true, Computed: true,
+				ElementType: types.StringType,
+				Description: "Parent hosts, e.g. the switch the host is connected to (addParentToHost / deleteParentToHost). " +
+					"Nagios does not notify about hosts that are unreachable because a parent is down. " +
+					"Omit to leave the host's parents unmanaged.",
+				PlanModifiers: []planmodifier.Set{setplanmodifier.UseStateForUnknown()},
+				Validators:    []validator.Set{objectNamesValidator{}},
 			},
 			"export_configuration": schema.BoolAttribute{
 				Optional: true, Computed: true,
 				Default:     booldefault.StaticBool(false),
 				Description: "Reload Nagios config after change (default false). Use eon_export_configuration instead.",
 			},
+			"custom_variables": schema.MapAttribute{
+				Optional: true, Computed: true,
+				ElementType: types.StringType,
+				Description: "Nagios custom variables, e.g. { _CMDB_ID = \"CI00042\" } " +
+					"(addCustomArgumentsToHost / modifyCustomArgumentsToHost / deleteCustomArgumentsToHost). " +
+					"Omit to leave the host's custom variables unmanaged.",
+				PlanModifiers: []planmodifier.Map{mapplanmodifier.UseStateForUnknown()},
+				Validators:    []validator.Map{customVariablesValidator{}},
+			},
+			"sensitive_custom_variables": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: "Custom variables holding secrets, e.g. { _SNMP_COMMUNITY = var.community }. " +
+					"Values are hidden in plans; only the variables listed here are managed.",
+				Validators: []validator.Map{customVariablesValidator{}},
+			},
+			"max_check_attempts": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Checks before a problem becomes a HARD state. Omit to inherit from the templates.",
+				Validators:  []validator.Int64{atLeastValidator{1}},
+			},
+			"check_interval": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minutes between regular checks. Omit to inherit from the templates.",
+				Validators:  []validator.Int64{atLeastValidator{1}},
+			},
+			"retry_interval": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minutes between checks while in a SOFT problem state. Omit to inherit from the templates.",
+				Validators:  []validator.Int64{atLeastValidator{1}},
+			},
+			"active_checks_enabled": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether Nagios schedules checks of the host. Omit to inherit from the templates.",
+			},
+			"passive_checks_enabled": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether Nagios accepts passive check results for the host. Omit to inherit from the templates.",
+			},
+			"notification_interval": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minutes between repeated notifications, 0 to notify once. Omit to inherit from the templates.",
+				Validators:  []validator.Int64{atLeastValidator{0}},
+			},
+			"notification_period": schema.StringAttribute{
+				Optional:    true,
+				Description: "Timeperiod in which notifications are sent (see eon_timeperiod). Omit to inherit from the templates.",
+				Validators:  []validator.String{objectNameValidator{}},
+			},
+			"flap_detection_enabled": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether Nagios detects flapping states. Omit to inherit from the templates.",
+			},
 		},
 	}
 }
 
+func (r *hostResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var vars, secrets types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("custom_variables"), &vars)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sensitive_custom_variables"), &secrets)...)
+	if !vars.IsUnknown() && !secrets.IsUnknown() {
+		for k := range secrets.Elements() {
+			if _, ok := vars.Elements()[k]; ok {
+				resp.Diagnostics.AddAttributeError(path.Root("sensitive_custom_variables").AtMapKey(k),
+					"Duplicate custom variable",
+					fmt.Sprintf("%s is set in both custom_variables and sensitive_custom_variables.", k))
+			}
+		}
+	}
+
+	var cfg hostModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	for _, c := range []struct {
+		single, plural string
+		both           bool
+	}{
+		{"template", "templates", !cfg.Template.IsNull() && !cfg.Templates.IsNull()},
+		{"contact", "contacts", !cfg.Contact.IsNull() && !cfg.Contacts.IsNull()},
+		{"contact_group", "contact_groups", !cfg.ContactGroup.IsNull() && !cfg.ContactGroups.IsNull()},
+	} {
+		if c.both {
+			resp.Diagnostics.AddAttributeError(path.Root(c.single), "Conflicting attributes",
+				fmt.Sprintf("%s cannot be set together with %s.", c.single, c.plural))
+		}
+	}
+
+	var name types.String
+	var parents types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("parents"), &parents)...)
+	if !name.IsUnknown() && !parents.IsUnknown() && containsString(setStrings(ctx, parents), name.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("parents"), "Host is its own parent",
+			fmt.Sprintf("%q cannot be a parent of itself.", name.ValueString()))
+	}
+
+	var templates types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("templates"), &templates)...)
+	if templates.IsNull() || templates.IsUnknown() {
+		return
+	}
+	if len(templates.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("templates"), "Missing host template",
+			"createHost needs at least one template; omit templates to use GENERIC_HOST.")
+	}
+	seen := map[string]bool{}
+	for _, t := range listStrings(ctx, templates) {
+		if seen[t] {
+			resp.Diagnostics.AddAttributeError(path.Root("templates"), "Duplicate host template",
+				fmt.Sprintf("%q is listed more than once.", t))
+		}
+		seen[t] = true
+	}
+}
+
+// ModifyPlan maps the deprecated singular attributes onto their
+// replacements and rejects parent cycles between the hosts planned in this
+// run; EONAPI accepts them and Nagios only fails at the next export.
+func (r *hostResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan hostModel
+	var template types.String
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("template"), &template)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.applyDeprecated(template)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+
+	if r.parents == nil || resp.Diagnostics.HasError() || plan.Name.IsUnknown() || plan.Parents.IsUnknown() {
+		return
+	}
+	if cycle := r.parents.plan(plan.Name.ValueString(), setStrings(ctx, plan.Parents)); cycle != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("parents"), "Parent cycle",
+			fmt.Sprintf("Hosts %s form a parent cycle, which Nagios rejects.", strings.Join(cycle, " -> ")))
+	}
+}
+
 func (r *hostResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData != nil {
-		r.client = req.ProviderData.(*client.Client)
+		data := req.ProviderData.(*providerData)
+		r.client, r.parents = data.client, data.hostParents
 	}
 }
 
-func (r *hostResource) hostBody(m *hostModel) map[string]interface{} {
-	body := map[string]interface{}{
-		"templateHostName":    m.Template.ValueString(),
+// hostBody is the createHost body; the host starts with its first template.
+func (r *hostResource) hostBody(ctx context.Context, m *hostModel, export bool) map[string]interface{} {
+	tpl := ""
+	if t := listStrings(ctx, m.Templates); len(t) > 0 {
+		tpl = t[0]
+	}
+	return map[string]interface{}{
+		"templateHostName":    tpl,
 		"hostName":            m.Name.ValueString(),
 		"hostIp":              m.IP.ValueString(),
 		"hostAlias":           m.Alias.ValueString(),
-		"exportConfiguration": m.Export.ValueBool(),
+		"exportConfiguration": export,
+	}
+}
+
+// linkOp is one incremental change to an existing object. Only the last op
+// of a run asks EONAPI to export, so Nagios reloads once per apply.
+type linkOp func(export bool) (*client.APIResponse, error)
+
+// converge lists the calls that bring the links of the host from the from
+// model to the to model. Null or unknown sets in to are left alone.
+func (r *hostResource) converge(ctx context.Context, from, to *hostModel) []linkOp {
+	name := to.Name.ValueString()
+	var ops []linkOp
+	remove, add := listDiff(ctx, from.Templates, to.Templates)
+	for _, t := range remove {
+		t := t
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.DeleteHostTemplateToHost(t, name, e) })
+	}
+	for _, t := range add {
+		t := t
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.AddHostTemplateToHost(t, name, e) })
+	}
+	add, remove = setDiff(ctx, from.Contacts, to.Contacts)
+	for _, c := range remove {
+		c := c
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.DeleteContactToHost(c, name, e) })
+	}
+	for _, c := range add {
+		c := c
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.AddContactToHost(c, name, e) })
+	}
+	add, remove = setDiff(ctx, from.ContactGroups, to.ContactGroups)
+	for _, g := range remove {
+		g := g
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.DeleteContactGroupToHost(g, name, e) })
+	}
+	for _, g := range add {
+		g := g
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.AddContactGroupToHost(g, name, e) })
+	}
+	add, remove = setDiff(ctx, from.Parents, to.Parents)
+	for _, p := range remove {
+		p := p
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.DeleteParentToHost(p, name, e) })
+	}
+	for _, p := range add {
+		p := p
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.AddParentToHost(p, name, e) })
+	}
+	if params := parametersDiff(from.parameters(), to.parameters()); len(params) > 0 {
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.ModifyHostParameters(name, params, e) })
+	}
+	added, modified, unset := customVariablesDiff(ctx, from, to)
+	if len(unset) > 0 {
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.DeleteCustomArgumentsToHost(name, unset, e) })
+	}
+	if len(modified) > 0 {
+		ops = append(ops, func(e bool) (*client.APIResponse, error) {
+			return r.client.ModifyCustomArgumentsToHost(name, modified, e)
+		})
+	}
+	if len(added) > 0 {
+		ops = append(ops, func(e bool) (*client.APIResponse, error) { return r.client.AddCustomArgumentsToHost(name, added, e) })
+	}
+	return ops
+}
+
+// parameters returns the host directives set by m, as modifyHostParameters
+// expects them.
+func (m *hostModel) parameters() map[string]string {
+	out := map[string]string{}
+	setInt := func(k string, v types.Int64) {
+		if !v.IsNull() && !v.IsUnknown() {
+			out[k] = strconv.FormatInt(v.ValueInt64(), 10)
+		}
+	}
+	setBool := func(k string, v types.Bool) {
+		if !v.IsNull() && !v.IsUnknown() {
+			out[k] = "0"
+			if v.ValueBool() {
+				out[k] = "1"
+			}
+		}
+	}
+	setInt("max_check_attempts", m.MaxCheckAttempts)
+	setInt("check_interval", m.CheckInterval)
+	setInt("retry_interval", m.RetryInterval)
+	setBool("active_checks_enabled", m.ActiveChecks)
+	setBool("passive_checks_enabled", m.PassiveChecks)
+	setInt("notification_interval", m.NotificationInterval)
+	if !m.NotificationPeriod.IsNull() && !m.NotificationPeriod.IsUnknown() {
+		out["notification_period"] = m.NotificationPeriod.ValueString()
+	}
+	setBool("flap_detection_enabled", m.FlapDetection)
+	return out
+}
+
+// parametersDiff returns the directives to send to go from cur to want;
+// directives no longer set are sent empty so the host inherits them again.
+func parametersDiff(cur, want map[string]string) map[string]string {
+	out := map[string]string{}
+	for k, v := range want {
+		if cur[k] != v {
+			out[k] = v
+		}
+	}
+	for k := range cur {
+		if _, ok := want[k]; !ok {
+			out[k] = ""
+		}
+	}
+	return out
+}
+
+// customVariablesDiff compares the custom variables of two models, plain
+// and sensitive ones together so that moving a variable between the maps
+// costs nothing. Plain variables left unknown by the plan are unmanaged.
+func customVariablesDiff(ctx context.Context, from, to *hostModel) (add, modify map[string]string, remove []string) {
+	cur := mapStrings(ctx, from.CustomVariables)
+	for k, v := range mapStrings(ctx, from.SensitiveCustomVariables) {
+		cur[k] = v
+	}
+	plain := to.CustomVariables
+	if plain.IsUnknown() {
+		plain = from.CustomVariables
+	}
+	want := mapStrings(ctx, plain)
+	for k, v := range mapStrings(ctx, to.SensitiveCustomVariables) {
+		want[k] = v
+	}
+
+	add, modify = map[string]string{}, map[string]string{}
+	for k, v := range want {
+		old, ok := cur[k]
+		switch {
+		case !ok:
+			add[k] = v
+		case old != v:
+			modify[k] = v
+		}
+	}
+	for k := range cur {
+		if _, ok := want[k]; !ok {
+			remove = append(remove, k)
+		}
+	}
+	sort.Strings(remove)
+	return add, modify, remove
+}
+
+func applyLinkOps(ops []linkOp, export bool) error {
+	for i, op := range ops {
+		if _, err := op(export && i == len(ops)-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// create creates the host with its first template, then converges it from
+// there to m.
+func (r *hostResource) create(ctx context.Context, m *hostModel) error {
+	initial := &hostModel{
+		Templates:                types.ListNull(types.StringType),
+		CustomVariables:          types.MapNull(types.StringType),
+		SensitiveCustomVariables: types.MapNull(types.StringType),
 	}
-	if !m.Contact.IsNull() && !m.Contact.IsUnknown() {
-		body["contactName"] = m.Contact.ValueString()
+	if t := listStrings(ctx, m.Templates); len(t) > 0 {
+		initial.Templates = stringListValue(t[:1])
 	}
-	if !m.ContactGroup.IsNull() && !m.ContactGroup.IsUnknown() {
-		body["contactGroupName"] = m.ContactGroup.ValueString()
+	ops := r.converge(ctx, initial, m)
+	export := m.Export.ValueBool()
+	if _, err := r.client.CreateHost(r.hostBody(ctx, m, export && len(ops) == 0)); err != nil {
+		return err
+	}
+	return applyLinkOps(ops, export)
+}
+
+// applyDeprecated sets templates, contacts and contact_groups from the
+// deprecated singular attributes, which ValidateConfig keeps from being set
+// together with them. template is computed, so its configured value is
+// passed in; the planned one always mirrors the first template.
+func (m *hostModel) applyDeprecated(template types.String) {
+	switch {
+	case template.IsUnknown():
+		m.Templates = types.ListUnknown(types.StringType)
+	case !template.IsNull():
+		m.Templates = stringListValue([]string{template.ValueString()})
+	}
+	m.Template = firstTemplate(m.Templates)
+	if !m.Contact.IsNull() {
+		m.Contacts = aliasSet(m.Contact)
+	}
+	if !m.ContactGroup.IsNull() {
+		m.ContactGroups = aliasSet(m.ContactGroup)
+	}
+}
+
+// firstTemplate is the value of the deprecated template attribute: the
+// first of templates.
+func firstTemplate(l types.List) types.String {
+	if l.IsUnknown() {
+		return types.StringUnknown()
+	}
+	if e := l.Elements(); len(e) > 0 {
+		if t, ok := e[0].(types.String); ok {
+			return t
+		}
+	}
+	return types.StringNull()
+}
+
+// aliasSet is the set holding the value of a deprecated singular attribute.
+func aliasSet(v types.String) types.Set {
+	if v.IsUnknown() {
+		return types.SetUnknown(types.StringType)
+	}
+	return stringSetValue([]string{v.ValueString()})
+}
+
+// settle replaces sets left unknown by the plan: nothing was linked for them.
+func (m *hostModel) settle() {
+	if m.Contacts.IsUnknown() {
+		m.Contacts = types.SetValueMust(types.StringType, []attr.Value{})
+	}
+	if m.ContactGroups.IsUnknown() {
+		m.ContactGroups = types.SetValueMust(types.StringType, []attr.Value{})
+	}
+	if m.Parents.IsUnknown() {
+		m.Parents = types.SetValueMust(types.StringType, []attr.Value{})
+	}
+	if m.CustomVariables.IsUnknown() {
+		m.CustomVariables = stringMapValue(nil)
 	}
-	return body
 }
 
 func (r *hostResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -116,13 +562,13 @@ func (r *hostResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	tflog.Info(ctx, "Creating EON host", map[string]interface{}{"name": plan.Name.ValueString()})
 
-	_, err := r.client.CreateHost(r.hostBody(&plan))
-	if err != nil {
+	if err := r.create(ctx, &plan); err != nil {
 		resp.Diagnostics.AddError("Error creating host", err.Error())
 		return
 	}
 
 	plan.ID = plan.Name
+	plan.settle()
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -133,40 +579,169 @@ func (r *hostResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	_, err := r.client.GetHost(state.Name.ValueString())
+	apiResp, err := r.client.GetHost(state.Name.ValueString())
 	if err != nil {
 		// If 404 / not found, remove from state
 		resp.State.RemoveResource(ctx)
 		return
 	}
-	// EONAPI getHost doesn't return structured fields we can map back cleanly,
-	// so we trust the Terraform state for attribute values.
+	h := client.DecodeHost(client.FirstObject(apiResp))
+	state.IP = refreshed(state.IP, h.Address)
+	state.Alias = refreshed(state.Alias, h.Alias)
+	state.Templates = refreshedList(state.Templates, h.Templates)
+	state.Template = firstTemplate(state.Templates)
+	state.Contacts = refreshedSet(state.Contacts, h.Contacts)
+	state.ContactGroups = refreshedSet(state.ContactGroups, h.ContactGroups)
+	state.Parents = refreshedSet(state.Parents, h.Parents)
+	state.refreshCustomVariables(h.CustomVariables)
+	state.refreshParameters(h.Parameters)
+	state.ID = state.Name
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *hostResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan hostModel
+	var plan, state hostModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	name := plan.Name.ValueString()
 
-	tflog.Info(ctx, "Updating EON host (delete+create)", map[string]interface{}{"name": plan.Name.ValueString()})
+	if plan.IP.Equal(state.IP) && plan.Alias.Equal(state.Alias) {
+		tflog.Info(ctx, "Updating EON host links", map[string]interface{}{"name": name})
 
-	// EONAPI has no modifyHost – delete then recreate
-	if _, err := r.client.DeleteHost(plan.Name.ValueString(), false); err != nil {
-		resp.Diagnostics.AddError("Error deleting host for update", err.Error())
-		return
-	}
-	if _, err := r.client.CreateHost(r.hostBody(&plan)); err != nil {
-		resp.Diagnostics.AddError("Error recreating host", err.Error())
-		return
+		if err := applyLinkOps(r.converge(ctx, &state, &plan), plan.Export.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Error updating host", err.Error())
+			return
+		}
+	} else {
+		tflog.Info(ctx, "Updating EON host (delete+create)", map[string]interface{}{"name": name})
+
+		// EONAPI has no modifyHost: address and alias changes need
+		// delete+create. The delete also drops the host from the parents of
+		// other hosts, so these links are added back after the create.
+		children, err := r.children(name)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading child hosts", err.Error())
+			return
+		}
+		if _, err := r.client.DeleteHost(name, false); err != nil {
+			resp.Diagnostics.AddError("Error deleting host for update", err.Error())
+			return
+		}
+		// Unmanaged links are restored as they were.
+		if plan.Contacts.IsUnknown() {
+			plan.Contacts = state.Contacts
+		}
+		if plan.ContactGroups.IsUnknown() {
+			plan.ContactGroups = state.ContactGroups
+		}
+		if plan.Parents.IsUnknown() {
+			plan.Parents = state.Parents
+		}
+		if plan.CustomVariables.IsUnknown() {
+			plan.CustomVariables = state.CustomVariables
+		}
+		if err := r.create(ctx, &plan); err != nil {
+			resp.Diagnostics.AddError("Error recreating host", err.Error())
+			return
+		}
+		for _, child := range children {
+			_, err := r.client.AddParentToHost(name, child, plan.Export.ValueBool())
+			if err != nil && !errors.Is(err, client.ErrNotFound) {
+				resp.Diagnostics.AddError("Error restoring child host",
+					fmt.Sprintf("Could not add %q back as a parent of %q: %s", name, child, err))
+			}
+		}
 	}
 
 	plan.ID = plan.Name
+	plan.settle()
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// children lists the hosts with name as a parent: those EON reports, and
+// those planned in this run whose links may not be exported yet.
+func (r *hostResource) children(name string) ([]string, error) {
+	hosts, err := r.client.ListHosts()
+	if err != nil {
+		return nil, err
+	}
+	var children []string
+	if r.parents != nil {
+		children = r.parents.children(name)
+	}
+	for _, h := range hosts {
+		if containsString(h.Parents, name) && !containsString(children, h.Name) {
+			children = append(children, h.Name)
+		}
+	}
+	sort.Strings(children)
+	return children, nil
+}
+
+// refreshCustomVariables splits the host's custom variables between the two
+// maps: the sensitive map keeps only the variables it already manages, the
+// plain one gets the rest. Like refreshed, the state is kept when the
+// response does not carry the variables.
+func (m *hostModel) refreshCustomVariables(api map[string]string) {
+	if api == nil {
+		return
+	}
+	plain := map[string]string{}
+	for k, v := range api {
+		plain[k] = v
+	}
+	if !m.SensitiveCustomVariables.IsNull() {
+		secrets := map[string]string{}
+		for k := range m.SensitiveCustomVariables.Elements() {
+			if v, ok := api[k]; ok {
+				secrets[k] = v
+				delete(plain, k)
+			}
+		}
+		m.SensitiveCustomVariables = stringMapValue(secrets)
+	}
+	m.CustomVariables = stringMapValue(plain)
+}
+
+// refreshParameters refreshes the directives set in the state. Null ones
+// are left alone: EON reports the value inherited from the templates.
+func (m *hostModel) refreshParameters(api map[string]string) {
+	m.MaxCheckAttempts = refreshedInt64(m.MaxCheckAttempts, api["max_check_attempts"])
+	m.CheckInterval = refreshedInt64(m.CheckInterval, api["check_interval"])
+	m.RetryInterval = refreshedInt64(m.RetryInterval, api["retry_interval"])
+	m.ActiveChecks = refreshedBool(m.ActiveChecks, api["active_checks_enabled"])
+	m.PassiveChecks = refreshedBool(m.PassiveChecks, api["passive_checks_enabled"])
+	m.NotificationInterval = refreshedInt64(m.NotificationInterval, api["notification_interval"])
+	if !m.NotificationPeriod.IsNull() {
+		m.NotificationPeriod = refreshed(m.NotificationPeriod, api["notification_period"])
+	}
+	m.FlapDetection = refreshedBool(m.FlapDetection, api["flap_detection_enabled"])
+}
+
+// refreshedInt64 is refreshed for numbers that are only managed when set.
+// livestatus reports intervals as floats.
+func refreshedInt64(cur types.Int64, api string) types.Int64 {
+	if cur.IsNull() || api == "" {
+		return cur
+	}
+	f, err := strconv.ParseFloat(api, 64)
+	if err != nil {
+		return cur
+	}
+	return types.Int64Value(int64(f))
+}
+
+// refreshedBool is refreshed for flags that are only managed when set.
+func refreshedBool(cur types.Bool, api string) types.Bool {
+	if cur.IsNull() || api == "" {
+		return cur
+	}
+	return types.BoolValue(api == "1" || api == "true")
+}
+
 func (r *hostResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state hostModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -187,3 +762,203 @@ func (r *hostResource) ImportState(ctx context.Context, req resource.ImportState
 	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
 }
+
+// ─── State upgrades ───────────────────────────────────────────────
+
+// hostModelV0 is the version 0 state: a single contact and contact group.
+type hostModelV0 struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	IP           types.String `tfsdk:"ip"`
+	Alias        types.String `tfsdk:"alias"`
+	Template     types.String `tfsdk:"template"`
+	Contact      types.String `tfsdk:"contact"`
+	ContactGroup types.String `tfsdk:"contact_group"`
+	Export       types.Bool   `tfsdk:"export_configuration"`
+}
+
+// hostModelV1 is the version 1 state: a single template.
+type hostModelV1 struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	IP            types.String `tfsdk:"ip"`
+	Alias         types.String `tfsdk:"alias"`
+	Template      types.String `tfsdk:"template"`
+	Contacts      types.Set    `tfsdk:"contacts"`
+	ContactGroups types.Set    `tfsdk:"contact_groups"`
+	Export        types.Bool   `tfsdk:"export_configuration"`
+}
+
+func (r *hostResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	v0 := map[string]schema.Attribute{
+		"id":                   schema.StringAttribute{Computed: true},
+		"name":                 schema.StringAttribute{Required: true},
+		"ip":                   schema.StringAttribute{Required: true},
+		"alias":                schema.StringAttribute{Optional: true, Computed: true},
+		"template":             schema.StringAttribute{Optional: true, Computed: true},
+		"contact":              schema.StringAttribute{Optional: true},
+		"contact_group":        schema.StringAttribute{Optional: true},
+		"export_configuration": schema.BoolAttribute{Optional: true, Computed: true},
+	}
+	v1 := map[string]schema.Attribute{
+		"contacts":       schema.SetAttribute{Optional: true, Computed: true, ElementType: types.StringType},
+		"contact_groups": schema.SetAttribute{Optional: true, Computed: true, ElementType: types.StringType},
+	}
+	for k, a := range v0 {
+		if k != "contact" && k != "contact_group" {
+			v1[k] = a
+		}
+	}
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{Attributes: v0},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var old hostModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &old)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				resp.Diagnostics.Append(resp.State.Set(ctx, hostModel{
+					ID:            old.ID,
+					Name:          old.Name,
+					IP:            old.IP,
+					Alias:         old.Alias,
+					Templates:     templateList(old.Template),
+					Contacts:      singletonSet(old.Contact),
+					ContactGroups: singletonSet(old.ContactGroup),
+					Parents:       types.SetNull(types.StringType),
+					Export:        old.Export,
+
+					// The configuration may still use the deprecated attributes.
+					Template:     firstTemplate(templateList(old.Template)),
+					Contact:      old.Contact,
+					ContactGroup: old.ContactGroup,
+
+					CustomVariables:          types.MapNull(types.StringType),
+					SensitiveCustomVariables: types.MapNull(types.StringType),
+				})...)
+			},
+		},
+		1: {
+			PriorSchema: &schema.Schema{Attributes: v1},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var old hostModelV1
+				resp.Diagnostics.Append(req.State.Get(ctx, &old)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				resp.Diagnostics.Append(resp.State.Set(ctx, hostModel{
+					ID:            old.ID,
+					Name:          old.Name,
+					IP:            old.IP,
+					Alias:         old.Alias,
+					Templates:     templateList(old.Template),
+					Contacts:      old.Contacts,
+					ContactGroups: old.ContactGroups,
+					Parents:       types.SetNull(types.StringType),
+					Export:        old.Export,
+					Template:      firstTemplate(templateList(old.Template)),
+
+					CustomVariables:          types.MapNull(types.StringType),
+					SensitiveCustomVariables: types.MapNull(types.StringType),
+				})...)
+			},
+		},
+	}
+}
+
+func singletonSet(v types.String) types.Set {
+	if v.IsNull() || v.ValueString() == "" {
+		return types.SetNull(types.StringType)
+	}
+	return types.SetValueMust(types.StringType, []attr.Value{v})
+}
+
+// templateList turns a former single template into a templates list.
+func templateList(v types.String) types.List {
+	if v.IsNull() || v.ValueString() == "" {
+		return stringListValue([]string{})
+	}
+	return stringListValue([]string{v.ValueString()})
+}
+
+// ─── Set and list helpers ─────────────────────────────────────────
+
+// setStrings returns the elements of a string set; null and unknown sets
+// are empty.
+func setStrings(ctx context.Context, s types.Set) []string {
+	out := []string{}
+	if s.IsNull() || s.IsUnknown() {
+		return out
+	}
+	s.ElementsAs(ctx, &out, false)
+	return out
+}
+
+// setDiff returns what to add to and remove from cur to reach want, in
+// sorted order. A null or unknown want is unmanaged: nothing changes.
+func setDiff(ctx context.Context, cur, want types.Set) (add, remove []string) {
+	if want.IsNull() || want.IsUnknown() {
+		return nil, nil
+	}
+	c, w := setStrings(ctx, cur), setStrings(ctx, want)
+	for _, s := range w {
+		if !containsString(c, s) {
+			add = append(add, s)
+		}
+	}
+	for _, s := range c {
+		if !containsString(w, s) {
+			remove = append(remove, s)
+		}
+	}
+	sort.Strings(add)
+	sort.Strings(remove)
+	return add, remove
+}
+
+// refreshedSet is refreshed for string sets: the state is kept when the
+// response does not carry the field (nil), an empty set is drift.
+func refreshedSet(cur types.Set, api []string) types.Set {
+	if api == nil && !cur.IsNull() {
+		return cur
+	}
+	return stringSetValue(api)
+}
+
+// listStrings returns the elements of a string list; null and unknown lists
+// are empty.
+func listStrings(ctx context.Context, l types.List) []string {
+	out := []string{}
+	if l.IsNull() || l.IsUnknown() {
+		return out
+	}
+	l.ElementsAs(ctx, &out, false)
+	return out
+}
+
+// listDiff returns the elements to remove from cur and then append, in
+// order, to turn cur into want. EONAPI can only append templates, so
+// everything after the common prefix is removed and re-added. A null or
+// unknown want is unmanaged: nothing changes.
+func listDiff(ctx context.Context, cur, want types.List) (remove, add []string) {
+	if want.IsNull() || want.IsUnknown() {
+		return nil, nil
+	}
+	c, w := listStrings(ctx, cur), listStrings(ctx, want)
+	k := 0
+	for k < len(c) && k < len(w) && c[k] == w[k] {
+		k++
+	}
+	return c[k:], w[k:]
+}
+
+// refreshedList is refreshed for string lists: the state is kept when the
+// response does not carry the field (nil), an empty list is drift.
+func refreshedList(cur types.List, api []string) types.List {
+	if api == nil && !cur.IsNull() {
+		return cur
+	}
+	return stringListValue(api)
+}
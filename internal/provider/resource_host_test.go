@@ -0,0 +1,878 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccHost_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHostConfig("tfacc-host", "10.99.0.1", "first"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "id", "tfacc-host"),
+					resource.TestCheckResourceAttr("eon_host.test", "ip", "10.99.0.1"),
+					resource.TestCheckResourceAttr("eon_host.test", "alias", "first"),
+					resource.TestCheckResourceAttr("eon_host.test", "templates.#", "1"),
+					resource.TestCheckResourceAttr("eon_host.test", "templates.0", "GENERIC_HOST"),
+				),
+			},
+			{
+				Config: testAccHostConfig("tfacc-host", "10.99.0.2", "second"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "ip", "10.99.0.2"),
+					resource.TestCheckResourceAttr("eon_host.test", "alias", "second"),
+				),
+			},
+			{
+				ResourceName:            "eon_host.test",
+				ImportState:             true,
+				ImportStateId:           "tfacc-host",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"export_configuration"},
+			},
+			{
+				// Drift: the host is deleted outside Terraform.
+				PreConfig: func() {
+					if _, err := testAccClient().DeleteHost("tfacc-host", false); err != nil {
+						t.Fatalf("deleting host behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccHostConfig("tfacc-host", "10.99.0.2", "second"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccHostConfig("tfacc-host", "10.99.0.2", "second"),
+				Check:  resource.TestCheckResourceAttr("eon_host.test", "ip", "10.99.0.2"),
+			},
+		},
+	})
+}
+
+func TestAccHost_readCache(t *testing.T) {
+	const cached = `
+provider "eon" {
+  read_cache = true
+}
+`
+	var gets int
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// A bad TTL is rejected even with the cache off.
+				Config: `
+provider "eon" {
+  read_cache_ttl = "-1m"
+}
+` + testAccHostConfig("tfacc-cache", "10.99.4.1", "cached"),
+				ExpectError: regexp.MustCompile(`Invalid read_cache_ttl`),
+			},
+			{
+				Config: cached + testAccHostConfig("tfacc-cache", "10.99.4.1", "cached"),
+				Check:  testAccCountCalls("getHost", &gets),
+			},
+			{
+				// The refresh reads the host from the listNagiosObjects snapshot.
+				Config: cached + testAccHostConfig("tfacc-cache", "10.99.4.1", "cached"),
+				Check:  testAccCheckCallsUnchanged("getHost", &gets),
+			},
+		},
+	})
+}
+
+func TestAccHost_contacts(t *testing.T) {
+	var creates int
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHostContactsConfig(`["tfacc-c1"]`, `["tfacc-g1"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "contacts.#", "1"),
+					resource.TestCheckTypeSetElemAttr("eon_host.test", "contacts.*", "tfacc-c1"),
+					resource.TestCheckResourceAttr("eon_host.test", "contact_groups.#", "1"),
+					resource.TestCheckTypeSetElemAttr("eon_host.test", "contact_groups.*", "tfacc-g1"),
+					testAccCountCalls("createHost", &creates),
+				),
+			},
+			{
+				// Links change in place: the host is not recreated.
+				Config: testAccHostContactsConfig(`["tfacc-c1", "tfacc-c2"]`, `[]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "contacts.#", "2"),
+					resource.TestCheckTypeSetElemAttr("eon_host.test", "contacts.*", "tfacc-c2"),
+					resource.TestCheckResourceAttr("eon_host.test", "contact_groups.#", "0"),
+					testAccCheckCallsUnchanged("createHost", &creates),
+				),
+			},
+			{
+				ResourceName:            "eon_host.test",
+				ImportState:             true,
+				ImportStateId:           "tfacc-host-links",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"export_configuration"},
+			},
+			{
+				// Drift: a contact is unlinked outside Terraform.
+				PreConfig: func() {
+					if _, err := testAccClient().DeleteContactToHost("tfacc-c2", "tfacc-host-links", false); err != nil {
+						t.Fatalf("unlinking contact behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccHostContactsConfig(`["tfacc-c1", "tfacc-c2"]`, `[]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccHostContactsConfig(`["tfacc-c1", "tfacc-c2"]`, `[]`),
+				Check:  resource.TestCheckResourceAttr("eon_host.test", "contacts.#", "2"),
+			},
+			{
+				// Drift: every contact is unlinked outside Terraform.
+				PreConfig: func() {
+					for _, c := range []string{"tfacc-c1", "tfacc-c2"} {
+						if _, err := testAccClient().DeleteContactToHost(c, "tfacc-host-links", false); err != nil {
+							t.Fatalf("unlinking contact behind Terraform's back: %s", err)
+						}
+					}
+				},
+				Config:             testAccHostContactsConfig(`["tfacc-c1", "tfacc-c2"]`, `[]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccHostContactsConfig(`["tfacc-c1", "tfacc-c2"]`, `[]`),
+				Check:  resource.TestCheckResourceAttr("eon_host.test", "contacts.#", "2"),
+			},
+		},
+	})
+}
+
+func TestAccHost_deprecatedContact(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccHostDeprecatedContactConfig(`contact = "tfacc-c1"`, `contacts = ["tfacc-c1"]`),
+				ExpectError: regexp.MustCompile(`Conflicting attributes`),
+			},
+			{
+				Config: testAccHostDeprecatedContactConfig(`contact = "tfacc-c1"`, `contact_group = "tfacc-g1"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "contact", "tfacc-c1"),
+					resource.TestCheckResourceAttr("eon_host.test", "contacts.#", "1"),
+					resource.TestCheckTypeSetElemAttr("eon_host.test", "contacts.*", "tfacc-c1"),
+					resource.TestCheckResourceAttr("eon_host.test", "contact_groups.#", "1"),
+					resource.TestCheckTypeSetElemAttr("eon_host.test", "contact_groups.*", "tfacc-g1"),
+				),
+			},
+			{
+				Config: testAccHostDeprecatedContactConfig(`contact = "tfacc-c2"`, `contact_group = "tfacc-g1"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "contacts.#", "1"),
+					resource.TestCheckTypeSetElemAttr("eon_host.test", "contacts.*", "tfacc-c2"),
+				),
+			},
+			{
+				// Moving to the plural attributes keeps the links.
+				Config: testAccHostDeprecatedContactConfig(`contacts = ["tfacc-c2"]`, `contact_groups = ["tfacc-g1"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("eon_host.test", "contact"),
+					resource.TestCheckResourceAttr("eon_host.test", "contacts.#", "1"),
+					resource.TestCheckTypeSetElemAttr("eon_host.test", "contacts.*", "tfacc-c2"),
+					resource.TestCheckResourceAttr("eon_host.test", "contact_groups.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCountCalls records how often the local server saw endpoint.
+func testAccCountCalls(endpoint string, n *int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if testAccServer != nil {
+			*n = testAccServer.Calls(endpoint)
+		}
+		return nil
+	}
+}
+
+// testAccCheckCallsUnchanged fails if endpoint was called since
+// testAccCountCalls recorded n.
+func testAccCheckCallsUnchanged(endpoint string, n *int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if testAccServer != nil && testAccServer.Calls(endpoint) != *n {
+			return fmt.Errorf("%s called %d more times", endpoint, testAccServer.Calls(endpoint)-*n)
+		}
+		return nil
+	}
+}
+
+func TestAccHost_templates(t *testing.T) {
+	var creates int
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccHostTemplatesConfig(`[]`),
+				ExpectError: regexp.MustCompile(`Missing host template`),
+			},
+			{
+				Config:      testAccHostTemplatesConfig(`["LINUX", "LINUX"]`),
+				ExpectError: regexp.MustCompile(`Duplicate host template`),
+			},
+			{
+				Config: testAccHostTemplatesConfig(`["GENERIC_HOST", "LINUX", "HTTP"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "templates.#", "3"),
+					resource.TestCheckResourceAttr("eon_host.test", "templates.1", "LINUX"),
+					testAccCheckHostTemplates("tfacc-host-tpl", "GENERIC_HOST", "LINUX", "HTTP"),
+					testAccCountCalls("createHost", &creates),
+				),
+			},
+			{
+				// Reordered in place: the host is not recreated.
+				Config: testAccHostTemplatesConfig(`["GENERIC_HOST", "HTTP"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "templates.#", "2"),
+					testAccCheckHostTemplates("tfacc-host-tpl", "GENERIC_HOST", "HTTP"),
+					testAccCheckCallsUnchanged("createHost", &creates),
+				),
+			},
+			{
+				Config: testAccHostTemplatesConfig(`["LINUX", "GENERIC_HOST"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "templates.0", "LINUX"),
+					testAccCheckHostTemplates("tfacc-host-tpl", "LINUX", "GENERIC_HOST"),
+					testAccCheckCallsUnchanged("createHost", &creates),
+				),
+			},
+		},
+	})
+}
+
+func TestAccHost_deprecatedTemplate(t *testing.T) {
+	var creates int
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccHostDeprecatedTemplateConfig(`template = "LINUX"` + "\n" + `templates = ["LINUX"]`),
+				ExpectError: regexp.MustCompile(`Conflicting attributes`),
+			},
+			{
+				Config: testAccHostDeprecatedTemplateConfig(`template = "LINUX"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "template", "LINUX"),
+					resource.TestCheckResourceAttr("eon_host.test", "templates.#", "1"),
+					resource.TestCheckResourceAttr("eon_host.test", "templates.0", "LINUX"),
+					testAccCheckHostTemplates("tfacc-host-tpl-old", "LINUX"),
+					testAccCountCalls("createHost", &creates),
+				),
+			},
+			{
+				Config: testAccHostDeprecatedTemplateConfig(`template = "HTTP"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "template", "HTTP"),
+					testAccCheckHostTemplates("tfacc-host-tpl-old", "HTTP"),
+					testAccCheckCallsUnchanged("createHost", &creates),
+				),
+			},
+			{
+				// Moving to templates: template follows the first one.
+				Config: testAccHostDeprecatedTemplateConfig(`templates = ["HTTP", "GENERIC_HOST"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "template", "HTTP"),
+					resource.TestCheckResourceAttr("eon_host.test", "templates.#", "2"),
+					testAccCheckHostTemplates("tfacc-host-tpl-old", "HTTP", "GENERIC_HOST"),
+				),
+			},
+			{
+				Config:   testAccHostDeprecatedTemplateConfig(`templates = ["HTTP", "GENERIC_HOST"]`),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// testAccCheckHostTemplates checks the template order EON reports.
+func testAccCheckHostTemplates(host string, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		r, err := testAccClient().GetHost(host)
+		if err != nil {
+			return err
+		}
+		got := client.DecodeHost(client.FirstObject(r)).Templates
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			return fmt.Errorf("host %s templates = %v, want %v", host, got, want)
+		}
+		return nil
+	}
+}
+
+func TestAccHost_customVariables(t *testing.T) {
+	var creates int
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccHostCustomVariablesConfig(`{ cmdb_id = "CI1" }`, `{}`),
+				ExpectError: regexp.MustCompile(`Invalid custom variable name`),
+			},
+			{
+				Config:      testAccHostCustomVariablesConfig(`{ _CMDB_ID = "CI1" }`, `{ _CMDB_ID = "CI1" }`),
+				ExpectError: regexp.MustCompile(`Duplicate custom variable`),
+			},
+			{
+				Config: testAccHostCustomVariablesConfig(`{ _CMDB_ID = "CI1", _SITE = "paris" }`, `{ _SNMP_COMMUNITY = "s3cret" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "custom_variables.%", "2"),
+					resource.TestCheckResourceAttr("eon_host.test", "custom_variables._CMDB_ID", "CI1"),
+					resource.TestCheckResourceAttr("eon_host.test", "sensitive_custom_variables._SNMP_COMMUNITY", "s3cret"),
+					testAccCheckHostCustomVariables("tfacc-host-vars",
+						map[string]string{"_CMDB_ID": "CI1", "_SITE": "paris", "_SNMP_COMMUNITY": "s3cret"}),
+					testAccCountCalls("createHost", &creates),
+				),
+			},
+			{
+				// Changed in place: the host is not recreated.
+				Config: testAccHostCustomVariablesConfig(`{ _CMDB_ID = "CI2" }`, `{ _SNMP_COMMUNITY = "n3w" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "custom_variables.%", "1"),
+					testAccCheckHostCustomVariables("tfacc-host-vars",
+						map[string]string{"_CMDB_ID": "CI2", "_SNMP_COMMUNITY": "n3w"}),
+					testAccCheckCallsUnchanged("createHost", &creates),
+				),
+			},
+			{
+				// An imported host reports every variable as plain.
+				ResourceName:      "eon_host.test",
+				ImportState:       true,
+				ImportStateId:     "tfacc-host-vars",
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{"export_configuration", "sensitive_custom_variables",
+					"custom_variables.%", "custom_variables._SNMP_COMMUNITY"},
+			},
+			{
+				// Drift: a variable is changed outside Terraform.
+				PreConfig: func() {
+					_, err := testAccClient().ModifyCustomArgumentsToHost("tfacc-host-vars",
+						map[string]string{"_SNMP_COMMUNITY": "public"}, false)
+					if err != nil {
+						t.Fatalf("changing custom variable behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccHostCustomVariablesConfig(`{ _CMDB_ID = "CI2" }`, `{ _SNMP_COMMUNITY = "n3w" }`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Moving a variable to the plain map costs no call.
+				Config: testAccHostCustomVariablesConfig(`{ _CMDB_ID = "CI2", _SNMP_COMMUNITY = "n3w" }`, `{}`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "custom_variables.%", "2"),
+					resource.TestCheckResourceAttr("eon_host.test", "sensitive_custom_variables.%", "0"),
+					testAccCheckHostCustomVariables("tfacc-host-vars",
+						map[string]string{"_CMDB_ID": "CI2", "_SNMP_COMMUNITY": "n3w"}),
+				),
+			},
+			{
+				// Drift: every variable is deleted outside Terraform.
+				PreConfig: func() {
+					_, err := testAccClient().DeleteCustomArgumentsToHost("tfacc-host-vars",
+						[]string{"_CMDB_ID", "_SNMP_COMMUNITY"}, false)
+					if err != nil {
+						t.Fatalf("deleting custom variables behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccHostCustomVariablesConfig(`{ _CMDB_ID = "CI2", _SNMP_COMMUNITY = "n3w" }`, `{}`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// testAccCheckHostCustomVariables checks the custom variables EON reports.
+func testAccCheckHostCustomVariables(host string, want map[string]string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		r, err := testAccClient().GetHost(host)
+		if err != nil {
+			return err
+		}
+		got := client.DecodeHost(client.FirstObject(r)).CustomVariables
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return fmt.Errorf("host %s custom variables = %v, want %v", host, got, want)
+		}
+		return nil
+	}
+}
+
+func TestAccHost_parents(t *testing.T) {
+	var creates int
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccHostParentsConfig(`["tfacc-web"]`),
+				ExpectError: regexp.MustCompile(`Host is its own parent`),
+			},
+			{
+				// web -> loop -> web, with literal names Terraform cannot
+				// order.
+				Config: testAccHostParentsConfig(`["tfacc-loop"]`) + `
+resource "eon_host" "loop" {
+  name    = "tfacc-loop"
+  ip      = "10.99.1.4"
+  parents = ["tfacc-web"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Parent cycle`),
+			},
+			{
+				Config: testAccHostParentsConfig(`[eon_host.sw.name]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.core", "parents.#", "0"),
+					resource.TestCheckTypeSetElemAttr("eon_host.sw", "parents.*", "tfacc-core"),
+					resource.TestCheckResourceAttr("eon_host.web", "parents.#", "1"),
+					resource.TestCheckTypeSetElemAttr("eon_host.web", "parents.*", "tfacc-sw"),
+					testAccCountCalls("createHost", &creates),
+				),
+			},
+			{
+				// Changed in place: the host is not recreated.
+				Config: testAccHostParentsConfig(`[eon_host.sw.name, eon_host.core.name]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.web", "parents.#", "2"),
+					resource.TestCheckTypeSetElemAttr("eon_host.web", "parents.*", "tfacc-core"),
+					testAccCheckCallsUnchanged("createHost", &creates),
+				),
+			},
+			{
+				ResourceName:            "eon_host.web",
+				ImportState:             true,
+				ImportStateId:           "tfacc-web",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"export_configuration"},
+			},
+			{
+				// Drift: a parent is unlinked outside Terraform.
+				PreConfig: func() {
+					if _, err := testAccClient().DeleteParentToHost("tfacc-core", "tfacc-web", false); err != nil {
+						t.Fatalf("unlinking parent behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccHostParentsConfig(`[eon_host.sw.name, eon_host.core.name]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccHostParentsConfig(`[eon_host.sw.name, eon_host.core.name]`),
+				Check:  resource.TestCheckResourceAttr("eon_host.web", "parents.#", "2"),
+			},
+			{
+				// Recreating core for its address drops it from the parents
+				// of its children, managed or not; they are linked again.
+				PreConfig: func() {
+					c := testAccClient()
+					if _, err := c.CreateHost(map[string]interface{}{"hostName": "tfacc-edge", "hostIp": "10.99.1.5"}); err != nil {
+						t.Fatalf("creating host behind Terraform's back: %s", err)
+					}
+					if _, err := c.AddParentToHost("tfacc-core", "tfacc-edge", false); err != nil {
+						t.Fatalf("linking parent behind Terraform's back: %s", err)
+					}
+					t.Cleanup(func() { c.DeleteHost("tfacc-edge", false) })
+				},
+				Config: strings.Replace(testAccHostParentsConfig(`[eon_host.sw.name, eon_host.core.name]`), "10.99.1.1", "10.99.1.10", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.core", "ip", "10.99.1.10"),
+					testAccCheckHostParents("tfacc-sw", "tfacc-core"),
+					testAccCheckHostParents("tfacc-web", "tfacc-core", "tfacc-sw"),
+					testAccCheckHostParents("tfacc-edge", "tfacc-core"),
+				),
+			},
+			{
+				Config:   strings.Replace(testAccHostParentsConfig(`[eon_host.sw.name, eon_host.core.name]`), "10.99.1.1", "10.99.1.10", 1),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// testAccCheckHostParents checks the parents EON reports for host.
+func testAccCheckHostParents(host string, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		r, err := testAccClient().GetHost(host)
+		if err != nil {
+			return err
+		}
+		got := client.DecodeHost(client.FirstObject(r)).Parents
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			return fmt.Errorf("host %s parents = %v, want %v", host, got, want)
+		}
+		return nil
+	}
+}
+
+func TestAccHost_tuning(t *testing.T) {
+	var creates int
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccHostTuningConfig(`max_check_attempts = 0`),
+				ExpectError: regexp.MustCompile(`Value too small`),
+			},
+			{
+				Config: testAccHostTuningConfig(`
+  max_check_attempts    = 5
+  check_interval        = 10
+  active_checks_enabled = false
+  notification_period   = "workhours"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_host.test", "max_check_attempts", "5"),
+					resource.TestCheckResourceAttr("eon_host.test", "active_checks_enabled", "false"),
+					resource.TestCheckNoResourceAttr("eon_host.test", "retry_interval"),
+					testAccCheckHostParameters("tfacc-host-tuning", map[string]string{
+						"max_check_attempts": "5", "check_interval": "10",
+						"active_checks_enabled": "0", "notification_period": "workhours",
+					}),
+					testAccCountCalls("createHost", &creates),
+				),
+			},
+			{
+				// Removed directives are inherited again, in place.
+				Config: testAccHostTuningConfig(`
+  check_interval        = 15
+  active_checks_enabled = false`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("eon_host.test", "max_check_attempts"),
+					testAccCheckHostParameters("tfacc-host-tuning", map[string]string{
+						"check_interval": "15", "active_checks_enabled": "0",
+					}),
+					testAccCheckCallsUnchanged("createHost", &creates),
+				),
+			},
+			{
+				// Import cannot tell the host's own directives from
+				// inherited ones and leaves them unset.
+				ResourceName:      "eon_host.test",
+				ImportState:       true,
+				ImportStateId:     "tfacc-host-tuning",
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{"export_configuration",
+					"check_interval", "active_checks_enabled"},
+			},
+			{
+				// Drift: a directive is changed outside Terraform.
+				PreConfig: func() {
+					_, err := testAccClient().ModifyHostParameters("tfacc-host-tuning",
+						map[string]string{"check_interval": "30"}, false)
+					if err != nil {
+						t.Fatalf("changing host parameters behind Terraform's back: %s", err)
+					}
+				},
+				Config: testAccHostTuningConfig(`
+  check_interval        = 15
+  active_checks_enabled = false`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccHostTuningConfig(`
+  check_interval        = 15
+  active_checks_enabled = false`),
+				Check: resource.TestCheckResourceAttr("eon_host.test", "check_interval", "15"),
+			},
+		},
+	})
+}
+
+// testAccCheckHostParameters checks the directives EON reports.
+func testAccCheckHostParameters(host string, want map[string]string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		r, err := testAccClient().GetHost(host)
+		if err != nil {
+			return err
+		}
+		got := client.DecodeHost(client.FirstObject(r)).Parameters
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return fmt.Errorf("host %s parameters = %v, want %v", host, got, want)
+		}
+		return nil
+	}
+}
+
+func TestAccHost_validation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccHostConfig("tfacc;host", "10.0.0.1", ""),
+				ExpectError: regexp.MustCompile(`Invalid Nagios object name`),
+			},
+			{
+				Config:      testAccHostConfig("tfacc-host", "10.0.0.256", ""),
+				ExpectError: regexp.MustCompile(`Invalid address`),
+			},
+			{
+				Config:      testAccHostConfig("tfacc-host", "web_01.example.com", ""),
+				ExpectError: regexp.MustCompile(`Invalid address`),
+			},
+		},
+	})
+}
+
+// upgradeHostState runs the host state upgrader for version on a prior
+// state built from attrs.
+func upgradeHostState(t *testing.T, version int64, attrs map[string]tftypes.Value) hostModel {
+	t.Helper()
+	var got hostModel
+	upgradeState(t, &hostResource{}, version, attrs, &got)
+	return got
+}
+
+// upgradeState runs the state upgrader of r for version on a prior state
+// built from attrs and reads the result into target.
+func upgradeState(t *testing.T, r fwresource.ResourceWithUpgradeState, version int64, attrs map[string]tftypes.Value, target interface{}) {
+	t.Helper()
+	ctx := context.Background()
+	up := r.UpgradeState(ctx)[version]
+	var current fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &current)
+
+	prior := tftypes.NewValue(up.PriorSchema.Type().TerraformType(ctx), attrs)
+	req := fwresource.UpgradeStateRequest{State: &tfsdk.State{Schema: *up.PriorSchema, Raw: prior}}
+	resp := &fwresource.UpgradeStateResponse{State: tfsdk.State{
+		Schema: current.Schema,
+		Raw:    tftypes.NewValue(current.Schema.Type().TerraformType(ctx), nil),
+	}}
+	up.StateUpgrader(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("upgrade from v%d: %v", version, resp.Diagnostics)
+	}
+	resp.Diagnostics.Append(resp.State.Get(ctx, target)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("reading upgraded state: %v", resp.Diagnostics)
+	}
+}
+
+func tfString(v interface{}) tftypes.Value { return tftypes.NewValue(tftypes.String, v) }
+
+func TestHostUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		contact, group interface{}
+		want           []string
+	}{
+		{contact: "ops", group: "admins", want: []string{"ops", "admins"}},
+		{contact: nil, group: nil},
+	} {
+		got := upgradeHostState(t, 0, map[string]tftypes.Value{
+			"id": tfString("web01"), "name": tfString("web01"), "ip": tfString("10.0.0.1"), "alias": tfString(""),
+			"template": tfString("GENERIC_HOST"), "contact": tfString(tc.contact), "contact_group": tfString(tc.group),
+			"export_configuration": tftypes.NewValue(tftypes.Bool, false),
+		})
+		if got.Name.ValueString() != "web01" || got.IP.ValueString() != "10.0.0.1" {
+			t.Errorf("upgraded scalars: got %s/%s", got.Name, got.IP)
+		}
+		if tpl := listStrings(ctx, got.Templates); len(tpl) != 1 || tpl[0] != "GENERIC_HOST" {
+			t.Errorf("templates = %v, want [GENERIC_HOST]", tpl)
+		}
+		if got.Template.ValueString() != "GENERIC_HOST" {
+			t.Errorf("template = %s, want GENERIC_HOST", got.Template)
+		}
+		if tc.want == nil {
+			if !got.Contacts.IsNull() || !got.ContactGroups.IsNull() {
+				t.Errorf("empty v0 links: got contacts %s, contact_groups %s, want null", got.Contacts, got.ContactGroups)
+			}
+			continue
+		}
+		if c := setStrings(ctx, got.Contacts); len(c) != 1 || c[0] != tc.want[0] {
+			t.Errorf("contacts = %v, want [%s]", c, tc.want[0])
+		}
+		if got.Contact.ValueString() != tc.want[0] || got.ContactGroup.ValueString() != tc.want[1] {
+			t.Errorf("deprecated contact, contact_group = %s, %s; want kept", got.Contact, got.ContactGroup)
+		}
+		if g := setStrings(ctx, got.ContactGroups); len(g) != 1 || g[0] != tc.want[1] {
+			t.Errorf("contact_groups = %v, want [%s]", g, tc.want[1])
+		}
+	}
+}
+
+func TestHostUpgradeStateV1(t *testing.T) {
+	ctx := context.Background()
+	set := tftypes.Set{ElementType: tftypes.String}
+	got := upgradeHostState(t, 1, map[string]tftypes.Value{
+		"id": tfString("web01"), "name": tfString("web01"), "ip": tfString("10.0.0.1"), "alias": tfString(""),
+		"template":             tfString("LINUX"),
+		"contacts":             tftypes.NewValue(set, []tftypes.Value{tfString("ops")}),
+		"contact_groups":       tftypes.NewValue(set, []tftypes.Value{}),
+		"export_configuration": tftypes.NewValue(tftypes.Bool, false),
+	})
+	if tpl := listStrings(ctx, got.Templates); len(tpl) != 1 || tpl[0] != "LINUX" {
+		t.Errorf("templates = %v, want [LINUX]", tpl)
+	}
+	if got.Template.ValueString() != "LINUX" {
+		t.Errorf("template = %s, want LINUX", got.Template)
+	}
+	if c := setStrings(ctx, got.Contacts); len(c) != 1 || c[0] != "ops" {
+		t.Errorf("contacts = %v, want [ops]", c)
+	}
+	if g := setStrings(ctx, got.ContactGroups); got.ContactGroups.IsNull() || len(g) != 0 {
+		t.Errorf("contact_groups = %s, want empty set", got.ContactGroups)
+	}
+}
+
+func testAccHostConfig(name, ip, alias string) string {
+	return fmt.Sprintf(`
+resource "eon_host" "test" {
+  name  = %q
+  ip    = %q
+  alias = %q
+}
+`, name, ip, alias)
+}
+
+func testAccHostContactsConfig(contacts, groups string) string {
+	return fmt.Sprintf(`
+resource "eon_contact" "c1" {
+  name = "tfacc-c1"
+  mail = "c1@example.com"
+}
+
+resource "eon_contact" "c2" {
+  name = "tfacc-c2"
+  mail = "c2@example.com"
+}
+
+resource "eon_contact_group" "g1" {
+  name = "tfacc-g1"
+}
+
+resource "eon_host" "test" {
+  name           = "tfacc-host-links"
+  ip             = "10.99.0.3"
+  contacts       = %s
+  contact_groups = %s
+
+  depends_on = [eon_contact.c1, eon_contact.c2, eon_contact_group.g1]
+}
+`, contacts, groups)
+}
+
+func testAccHostDeprecatedContactConfig(contacts, groups string) string {
+	return fmt.Sprintf(`
+resource "eon_contact" "c1" {
+  name = "tfacc-c1"
+  mail = "c1@example.com"
+}
+
+resource "eon_contact" "c2" {
+  name = "tfacc-c2"
+  mail = "c2@example.com"
+}
+
+resource "eon_contact_group" "g1" {
+  name = "tfacc-g1"
+}
+
+resource "eon_host" "test" {
+  name = "tfacc-host-deprecated"
+  ip   = "10.99.0.9"
+  %s
+  %s
+
+  depends_on = [eon_contact.c1, eon_contact.c2, eon_contact_group.g1]
+}
+`, contacts, groups)
+}
+
+func testAccHostTemplatesConfig(templates string) string {
+	return fmt.Sprintf(`
+resource "eon_host" "test" {
+  name      = "tfacc-host-tpl"
+  ip        = "10.99.0.4"
+  templates = %s
+}
+`, templates)
+}
+
+func testAccHostDeprecatedTemplateConfig(templates string) string {
+	return fmt.Sprintf(`
+resource "eon_host" "test" {
+  name = "tfacc-host-tpl-old"
+  ip   = "10.99.0.10"
+  %s
+}
+`, templates)
+}
+
+func testAccHostCustomVariablesConfig(vars, secrets string) string {
+	return fmt.Sprintf(`
+resource "eon_host" "test" {
+  name                       = "tfacc-host-vars"
+  ip                         = "10.99.0.5"
+  custom_variables           = %s
+  sensitive_custom_variables = %s
+}
+`, vars, secrets)
+}
+
+func testAccHostParentsConfig(webParents string) string {
+	return fmt.Sprintf(`
+resource "eon_host" "core" {
+  name    = "tfacc-core"
+  ip      = "10.99.1.1"
+  parents = []
+}
+
+resource "eon_host" "sw" {
+  name    = "tfacc-sw"
+  ip      = "10.99.1.2"
+  parents = [eon_host.core.name]
+}
+
+resource "eon_host" "web" {
+  name    = "tfacc-web"
+  ip      = "10.99.1.3"
+  parents = %s
+}
+`, webParents)
+}
+
+func testAccHostTuningConfig(attrs string) string {
+	return fmt.Sprintf(`
+resource "eon_host" "test" {
+  name = "tfacc-host-tuning"
+  ip   = "10.99.0.6"
+%s
+}
+`, attrs)
+}
@@ -0,0 +1,219 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &timeperiodResource{}
+	_ resource.ResourceWithImportState    = &timeperiodResource{}
+	_ resource.ResourceWithValidateConfig = &timeperiodResource{}
+)
+
+type timeperiodResource struct{ client *client.Client }
+
+type timeperiodModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	Alias      types.String `tfsdk:"alias"`
+	Weekdays   types.Map    `tfsdk:"weekdays"`
+	Exceptions types.Map    `tfsdk:"exceptions"`
+	Exclude    types.Set    `tfsdk:"exclude"`
+}
+
+func NewTimeperiodResource() resource.Resource { return &timeperiodResource{} }
+
+func (r *timeperiodResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_timeperiod"
+}
+
+func (r *timeperiodResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	emptyMap := types.MapValueMust(types.StringType, map[string]attr.Value{})
+	resp.Schema = schema.Schema{
+		Description: "Manages a Nagios timeperiod in EON (createTimeperiod / modifyTimeperiod / deleteTimeperiod).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{idFromName{}},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Timeperiod name (e.g. workhours).",
+				Validators:  []validator.String{objectNameValidator{}},
+			},
+			"alias": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Timeperiod description.",
+			},
+			"weekdays": schema.MapAttribute{
+				Optional: true, Computed: true,
+				ElementType: types.StringType,
+				Default:     mapdefault.StaticValue(emptyMap),
+				Description: "Time ranges by weekday, e.g. { monday = \"09:00-12:00,13:00-17:00\" }.",
+				Validators:  []validator.Map{timeRangesValidator{}},
+			},
+			"exceptions": schema.MapAttribute{
+				Optional: true, Computed: true,
+				ElementType: types.StringType,
+				Default:     mapdefault.StaticValue(emptyMap),
+				Description: "Time ranges by Nagios date exception, e.g. { \"december 25\" = \"00:00-00:00\", \"day 1 - 7\" = \"08:00-18:00\" }. " +
+					"Exceptions override weekdays.",
+				Validators: []validator.Map{timeRangesValidator{exceptions: true}},
+			},
+			"exclude": schema.SetAttribute{
+				Optional: true, Computed: true,
+				ElementType: types.StringType,
+				Default:     setdefault.StaticValue(stringSetValue(nil)),
+				Description: "Timeperiods whose times are removed from this one.",
+				Validators:  []validator.Set{objectNamesValidator{}},
+			},
+		},
+	}
+}
+
+func (r *timeperiodResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var cfg timeperiodModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() || cfg.Name.IsNull() || cfg.Name.IsUnknown() {
+		return
+	}
+	if containsString(setStrings(ctx, cfg.Exclude), cfg.Name.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("exclude"), "Timeperiod excludes itself",
+			fmt.Sprintf("%q cannot exclude itself.", cfg.Name.ValueString()))
+	}
+}
+
+func (r *timeperiodResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		r.client = req.ProviderData.(*providerData).client
+	}
+}
+
+func (r *timeperiodResource) timeperiodBody(ctx context.Context, m *timeperiodModel) map[string]interface{} {
+	exclude := setStrings(ctx, m.Exclude)
+	sort.Strings(exclude)
+	return map[string]interface{}{
+		"timeperiodName": m.Name.ValueString(),
+		"alias":          m.Alias.ValueString(),
+		"weekdays":       mapStrings(ctx, m.Weekdays),
+		"exceptions":     mapStrings(ctx, m.Exceptions),
+		"exclude":        exclude,
+	}
+}
+
+func (r *timeperiodResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan timeperiodModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Creating EON timeperiod", map[string]interface{}{"name": plan.Name.ValueString()})
+
+	if _, err := r.client.CreateTimeperiod(r.timeperiodBody(ctx, &plan)); err != nil {
+		resp.Diagnostics.AddError("Error creating timeperiod", err.Error())
+		return
+	}
+	plan.ID = plan.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *timeperiodResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state timeperiodModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiResp, err := r.client.GetTimeperiod(state.Name.ValueString())
+	if err != nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	tp := client.DecodeTimeperiod(client.FirstObject(apiResp))
+	state.Alias = refreshed(state.Alias, tp.Alias)
+	state.Weekdays = refreshedMap(state.Weekdays, tp.Weekdays)
+	state.Exceptions = refreshedMap(state.Exceptions, tp.Exceptions)
+	state.Exclude = refreshedSet(state.Exclude, tp.Exclude)
+	state.ID = state.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *timeperiodResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state timeperiodModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Updating EON timeperiod", map[string]interface{}{"name": state.Name.ValueString()})
+
+	body := r.timeperiodBody(ctx, &plan)
+	body["timeperiodName"] = state.Name.ValueString()
+	if plan.Name.ValueString() != state.Name.ValueString() {
+		body["newTimeperiodName"] = plan.Name.ValueString()
+	}
+	if _, err := r.client.ModifyTimeperiod(body); err != nil {
+		resp.Diagnostics.AddError("Error updating timeperiod", err.Error())
+		return
+	}
+	plan.ID = plan.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *timeperiodResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state timeperiodModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Deleting EON timeperiod", map[string]interface{}{"name": state.Name.ValueString()})
+
+	if _, err := r.client.DeleteTimeperiod(state.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Error deleting timeperiod",
+			fmt.Sprintf("Could not delete timeperiod %q: %s", state.Name.ValueString(), err))
+	}
+}
+
+func (r *timeperiodResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// mapStrings returns the elements of a string map; null and unknown maps
+// are empty.
+func mapStrings(ctx context.Context, m types.Map) map[string]string {
+	out := map[string]string{}
+	if m.IsNull() || m.IsUnknown() {
+		return out
+	}
+	m.ElementsAs(ctx, &out, false)
+	return out
+}
+
+// refreshedMap is refreshed for string maps: the state is kept when the
+// response does not carry the field (nil), an empty map is drift.
+func refreshedMap(cur types.Map, api map[string]string) types.Map {
+	if api == nil && !cur.IsNull() {
+		return cur
+	}
+	return stringMapValue(api)
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccTimeperiod_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccTimeperiodConfig("tfacc-tp", `{ Monday = "09:00-17:00" }`, `{}`),
+				ExpectError: regexp.MustCompile(`Invalid weekday`),
+			},
+			{
+				Config:      testAccTimeperiodConfig("tfacc-tp", `{ monday = "17:00-09:00" }`, `{}`),
+				ExpectError: regexp.MustCompile(`Invalid time range`),
+			},
+			{
+				Config:      testAccTimeperiodConfig("tfacc-tp", `{}`, `{ "xmas" = "00:00-00:00" }`),
+				ExpectError: regexp.MustCompile(`Invalid date exception`),
+			},
+			{
+				Config: testAccTimeperiodConfig("tfacc-tp",
+					`{ monday = "09:00-17:00", friday = "09:00-12:00,13:00-16:00" }`,
+					`{ "december 25" = "00:00-00:00" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_timeperiod.test", "id", "tfacc-tp"),
+					resource.TestCheckResourceAttr("eon_timeperiod.test", "weekdays.%", "2"),
+					resource.TestCheckResourceAttr("eon_timeperiod.test", "weekdays.friday", "09:00-12:00,13:00-16:00"),
+					resource.TestCheckResourceAttr("eon_timeperiod.test", "exceptions.december 25", "00:00-00:00"),
+					resource.TestCheckResourceAttr("eon_timeperiod.test", "exclude.#", "1"),
+				),
+			},
+			{
+				// Rename in place and change the ranges.
+				Config: testAccTimeperiodConfig("tfacc-tp2",
+					`{ monday = "08:00-18:00" }`,
+					`{ "day 1 - 7" = "08:00-12:00" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_timeperiod.test", "id", "tfacc-tp2"),
+					resource.TestCheckResourceAttr("eon_timeperiod.test", "weekdays.%", "1"),
+					resource.TestCheckResourceAttr("eon_timeperiod.test", "weekdays.monday", "08:00-18:00"),
+					resource.TestCheckResourceAttr("eon_timeperiod.test", "exceptions.day 1 - 7", "08:00-12:00"),
+				),
+			},
+			{
+				ResourceName:      "eon_timeperiod.test",
+				ImportState:       true,
+				ImportStateId:     "tfacc-tp2",
+				ImportStateVerify: true,
+			},
+			{
+				// Drift: the ranges are changed outside Terraform.
+				PreConfig: func() {
+					_, err := testAccClient().ModifyTimeperiod(map[string]interface{}{
+						"timeperiodName": "tfacc-tp2", "alias": "tfacc",
+						"weekdays": map[string]string{"monday": "00:00-24:00"},
+						"exclude":  []string{"tfacc-tp-holidays"},
+					})
+					if err != nil {
+						t.Fatalf("modifying timeperiod behind Terraform's back: %s", err)
+					}
+				},
+				Config: testAccTimeperiodConfig("tfacc-tp2",
+					`{ monday = "08:00-18:00" }`,
+					`{ "day 1 - 7" = "08:00-12:00" }`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccTimeperiodConfig("tfacc-tp2",
+					`{ monday = "08:00-18:00" }`,
+					`{ "day 1 - 7" = "08:00-12:00" }`),
+				Check: resource.TestCheckResourceAttr("eon_timeperiod.test", "weekdays.monday", "08:00-18:00"),
+			},
+		},
+	})
+}
+
+func testAccTimeperiodConfig(name, weekdays, exceptions string) string {
+	return fmt.Sprintf(`
+resource "eon_timeperiod" "holidays" {
+  name  = "tfacc-tp-holidays"
+  alias = "Holidays"
+
+  exceptions = {
+    "january 1" = "00:00-24:00"
+  }
+}
+
+resource "eon_timeperiod" "test" {
+  name       = %q
+  alias      = "tfacc"
+  weekdays   = %s
+  exceptions = %s
+  exclude    = [eon_timeperiod.holidays.name]
+}
+`, name, weekdays, exceptions)
+}
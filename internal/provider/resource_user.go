@@ -0,0 +1,285 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &userResource{}
+	_ resource.ResourceWithImportState    = &userResource{}
+	_ resource.ResourceWithValidateConfig = &userResource{}
+)
+
+// userResource manages an EON web account. The password is sent to EON but
+// never read back: EON only stores its hash. password_wo keeps it out of the
+// Terraform state too; password_wo_version says when to send it again.
+type userResource struct{ client *client.Client }
+
+type userModel struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Description   types.String `tfsdk:"description"`
+	Mail          types.String `tfsdk:"mail"`
+	UserGroup     types.String `tfsdk:"user_group"`
+	AuthType      types.String `tfsdk:"auth_type"`
+	LDAPDN        types.String `tfsdk:"ldap_dn"`
+	Password      types.String `tfsdk:"password"`
+	PasswordWO    types.String `tfsdk:"password_wo"`
+	PasswordWOVer types.Int64  `tfsdk:"password_wo_version"`
+	LinkedContact types.Bool   `tfsdk:"linked_contact"`
+}
+
+func NewUserResource() resource.Resource { return &userResource{} }
+
+func (r *userResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an EON web user (createUser / modifyUser / deleteUser).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"name": schema.StringAttribute{
+				Required:      true,
+				Description:   "Login name.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				Validators:    []validator.String{objectNameValidator{}},
+			},
+			"description": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Full name or description.",
+			},
+			"mail": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Email address; required with linked_contact.",
+				Validators:  []validator.String{emailValidator{}},
+			},
+			"user_group": schema.StringAttribute{
+				Required:    true,
+				Description: "EON user group (see eon_user_group).",
+				Validators:  []validator.String{objectNameValidator{}},
+			},
+			"auth_type": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Default:     stringdefault.StaticString("local"),
+				Description: "\"local\" (password stored by EON) or \"ldap\" (authenticated against the directory).",
+			},
+			"ldap_dn": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Distinguished name of an ldap user.",
+			},
+			"password": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "Password of a local user. Only sent to EON when created or changed; " +
+					"changes made outside Terraform are not detected. Stored in the Terraform state: prefer password_wo.",
+			},
+			"password_wo": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Description: "Password of a local user, never stored in the Terraform state (Terraform 1.11 or later). " +
+					"Sent to EON on create and whenever password_wo_version changes.",
+			},
+			"password_wo_version": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Change this value to send password_wo to EON again.",
+			},
+			"linked_contact": schema.BoolAttribute{
+				Optional: true, Computed: true,
+				Default: booldefault.StaticBool(false),
+				Description: "Create a Nagios contact with the user's name and mail along with the user. " +
+					"EON deletes it with the user.",
+				PlanModifiers: []planmodifier.Bool{boolplanmodifier.RequiresReplace()},
+			},
+		},
+	}
+}
+
+func (r *userResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var cfg userModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() || cfg.AuthType.IsUnknown() {
+		return
+	}
+	if !cfg.Password.IsNull() && !cfg.PasswordWO.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("password_wo"), "Conflicting attributes",
+			"password_wo cannot be set together with password.")
+	}
+	if !cfg.PasswordWOVer.IsNull() && cfg.PasswordWO.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("password_wo_version"), "Missing password_wo",
+			"password_wo_version only applies to password_wo.")
+	}
+	switch cfg.AuthType.ValueString() {
+	case "", "local":
+		if cfg.Password.IsNull() && cfg.PasswordWO.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("password"), "Missing password",
+				"Local users need a password or password_wo; set auth_type = \"ldap\" for directory users.")
+		}
+	case "ldap":
+		if !cfg.Password.IsNull() || !cfg.PasswordWO.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("password"), "Password of an ldap user",
+				"ldap users authenticate against the directory; remove password and password_wo.")
+		}
+		if cfg.LDAPDN.IsNull() || (!cfg.LDAPDN.IsUnknown() && cfg.LDAPDN.ValueString() == "") {
+			resp.Diagnostics.AddAttributeError(path.Root("ldap_dn"), "Missing LDAP DN",
+				"ldap users need the distinguished name of their directory entry.")
+		}
+	default:
+		resp.Diagnostics.AddAttributeError(path.Root("auth_type"), "Invalid authentication type",
+			fmt.Sprintf("%q is not an authentication type; use \"local\" or \"ldap\".", cfg.AuthType.ValueString()))
+	}
+	if cfg.LinkedContact.ValueBool() && !cfg.Mail.IsUnknown() && cfg.Mail.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("mail"), "Missing mail",
+			"The linked Nagios contact needs a mail address.")
+	}
+}
+
+func (r *userResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		r.client = req.ProviderData.(*providerData).client
+	}
+}
+
+func (r *userResource) userBody(m *userModel) map[string]interface{} {
+	return map[string]interface{}{
+		"userName":  m.Name.ValueString(),
+		"userDescr": m.Description.ValueString(),
+		"userMail":  m.Mail.ValueString(),
+		"userGroup": m.UserGroup.ValueString(),
+		"userType":  m.AuthType.ValueString(),
+		"ldapDn":    m.LDAPDN.ValueString(),
+	}
+}
+
+func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan userModel
+	var passwordWO types.String
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &passwordWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Creating EON user", map[string]interface{}{"name": plan.Name.ValueString()})
+
+	body := r.userBody(&plan)
+	body["createContact"] = plan.LinkedContact.ValueBool()
+	switch {
+	case !plan.Password.IsNull():
+		body["userPassword"] = plan.Password.ValueString()
+	case !passwordWO.IsNull():
+		body["userPassword"] = passwordWO.ValueString()
+	}
+	if _, err := r.client.CreateUser(body); err != nil {
+		resp.Diagnostics.AddError("Error creating user", err.Error())
+		return
+	}
+	plan.ID = plan.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state userModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiResp, err := r.client.GetUser(state.Name.ValueString())
+	if err != nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	u := client.DecodeUser(client.FirstObject(apiResp))
+	state.Description = refreshed(state.Description, u.Description)
+	state.Mail = refreshed(state.Mail, u.Mail)
+	state.UserGroup = refreshed(state.UserGroup, u.Group)
+	state.LDAPDN = refreshed(state.LDAPDN, u.LDAPDN)
+	// Not every EON returns user_type and nagios_contact: keep the state
+	// then, or assume EON's defaults on import.
+	switch {
+	case u.AuthType != "":
+		state.AuthType = types.StringValue(u.AuthType)
+	case state.AuthType.IsNull():
+		state.AuthType = types.StringValue("local")
+	}
+	switch {
+	case u.LinkedContact != nil:
+		state.LinkedContact = types.BoolValue(*u.LinkedContact)
+	case state.LinkedContact.IsNull():
+		state.LinkedContact = types.BoolValue(false)
+	}
+	state.ID = state.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state userModel
+	var passwordWO types.String
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &passwordWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Updating EON user", map[string]interface{}{"name": plan.Name.ValueString()})
+
+	body := r.userBody(&plan)
+	switch {
+	case !plan.Password.IsNull() && !plan.Password.Equal(state.Password):
+		body["userPassword"] = plan.Password.ValueString()
+	case !passwordWO.IsNull() && !plan.PasswordWOVer.Equal(state.PasswordWOVer):
+		// The write-only value is not in the state, so only a new version
+		// tells that it changed.
+		body["userPassword"] = passwordWO.ValueString()
+	}
+	if _, err := r.client.ModifyUser(body); err != nil {
+		resp.Diagnostics.AddError("Error updating user", err.Error())
+		return
+	}
+	plan.ID = plan.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state userModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Deleting EON user", map[string]interface{}{"name": state.Name.ValueString()})
+
+	if _, err := r.client.DeleteUser(state.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Error deleting user",
+			fmt.Sprintf("Could not delete user %q: %s", state.Name.ValueString(), err))
+	}
+}
+
+func (r *userResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
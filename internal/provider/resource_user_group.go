@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                = &userGroupResource{}
+	_ resource.ResourceWithImportState = &userGroupResource{}
+)
+
+type userGroupResource struct{ client *client.Client }
+
+type userGroupModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	LDAPGroup   types.String `tfsdk:"ldap_group"`
+}
+
+func NewUserGroupResource() resource.Resource { return &userGroupResource{} }
+
+func (r *userGroupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_group"
+}
+
+func (r *userGroupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an EON web user group (createUserGroup / modifyUserGroup / deleteUserGroup).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{idFromName{}},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Group name.",
+				Validators:  []validator.String{objectNameValidator{}},
+			},
+			"description": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Group description.",
+			},
+			"ldap_group": schema.StringAttribute{
+				Optional: true, Computed: true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Distinguished name of the directory group whose members get this group at login.",
+			},
+		},
+	}
+}
+
+func (r *userGroupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		r.client = req.ProviderData.(*providerData).client
+	}
+}
+
+func (r *userGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan userGroupModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Creating EON user group", map[string]interface{}{"name": plan.Name.ValueString()})
+
+	_, err := r.client.CreateUserGroup(map[string]interface{}{
+		"groupName":  plan.Name.ValueString(),
+		"groupDescr": plan.Description.ValueString(),
+		"ldapGroup":  plan.LDAPGroup.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating user group", err.Error())
+		return
+	}
+	plan.ID = plan.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *userGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state userGroupModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	apiResp, err := r.client.GetUserGroup(state.Name.ValueString())
+	if err != nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	g := client.DecodeUserGroup(client.FirstObject(apiResp))
+	state.Description = refreshed(state.Description, g.Description)
+	state.LDAPGroup = refreshed(state.LDAPGroup, g.LDAPGroup)
+	state.ID = state.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *userGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state userGroupModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Updating EON user group", map[string]interface{}{"name": state.Name.ValueString()})
+
+	body := map[string]interface{}{
+		"groupName":  state.Name.ValueString(),
+		"groupDescr": plan.Description.ValueString(),
+		"ldapGroup":  plan.LDAPGroup.ValueString(),
+	}
+	if plan.Name.ValueString() != state.Name.ValueString() {
+		body["newGroupName"] = plan.Name.ValueString()
+	}
+	if _, err := r.client.ModifyUserGroup(body); err != nil {
+		resp.Diagnostics.AddError("Error updating user group", err.Error())
+		return
+	}
+	plan.ID = plan.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *userGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state userGroupModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Deleting EON user group", map[string]interface{}{"name": state.Name.ValueString()})
+
+	if _, err := r.client.DeleteUserGroup(state.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Error deleting user group",
+			fmt.Sprintf("Could not delete user group %q: %s", state.Name.ValueString(), err))
+	}
+}
+
+func (r *userGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
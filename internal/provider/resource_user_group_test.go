@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccUserGroup_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserGroupConfig("tfacc-ugroup", "Operators"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_user_group.test", "id", "tfacc-ugroup"),
+					resource.TestCheckResourceAttr("eon_user_group.test", "description", "Operators"),
+				),
+			},
+			{
+				// Rename in place.
+				Config: testAccUserGroupConfig("tfacc-ugroup2", "Night shift"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_user_group.test", "id", "tfacc-ugroup2"),
+					resource.TestCheckResourceAttr("eon_user_group.test", "description", "Night shift"),
+				),
+			},
+			{
+				ResourceName:      "eon_user_group.test",
+				ImportState:       true,
+				ImportStateId:     "tfacc-ugroup2",
+				ImportStateVerify: true,
+			},
+			{
+				// Drift: the group is deleted outside Terraform.
+				PreConfig: func() {
+					if _, err := testAccClient().DeleteUserGroup("tfacc-ugroup2"); err != nil {
+						t.Fatalf("deleting user group behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccUserGroupConfig("tfacc-ugroup2", "Night shift"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserGroupConfig("tfacc-ugroup2", "Night shift"),
+				Check:  resource.TestCheckResourceAttr("eon_user_group.test", "description", "Night shift"),
+			},
+		},
+	})
+}
+
+func testAccUserGroupConfig(name, desc string) string {
+	return fmt.Sprintf(`
+resource "eon_user_group" "test" {
+  name        = %q
+  description = %q
+}
+`, name, desc)
+}
@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"crypto/md5"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/ktoulliou/terraform-provider-eon/internal/client"
+	"github.com/ktoulliou/terraform-provider-eon/internal/eontest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccUser_local(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserConfig("admins", "", "a@example.com"),
+				ExpectError: regexp.MustCompile(`Missing password`),
+			},
+			{
+				Config:      testAccUserConfig("admins", "s3cret", ""),
+				ExpectError: regexp.MustCompile(`Missing mail`),
+			},
+			{
+				Config: testAccUserConfig("admins", "s3cret", "jdoe@example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_user.test", "id", "tfacc-jdoe"),
+					resource.TestCheckResourceAttr("eon_user.test", "auth_type", "local"),
+					resource.TestCheckResourceAttr("eon_user.test", "user_group", "tfacc-admins"),
+					testAccCheckLinkedContact("tfacc-jdoe", "jdoe@example.com"),
+					testAccCheckUserPassword("tfacc-jdoe", "s3cret"),
+				),
+			},
+			{
+				// Group and password change in place.
+				Config: testAccUserConfig("operators", "n3w-s3cret", "jdoe@example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_user.test", "user_group", "tfacc-operators"),
+					testAccCheckUserPassword("tfacc-jdoe", "n3w-s3cret"),
+				),
+			},
+			{
+				ResourceName:            "eon_user.test",
+				ImportState:             true,
+				ImportStateId:           "tfacc-jdoe",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+			{
+				// Drift: the user is moved to another group outside Terraform.
+				PreConfig: func() {
+					_, err := testAccClient().ModifyUser(map[string]interface{}{"userName": "tfacc-jdoe", "userGroup": "tfacc-admins"})
+					if err != nil {
+						t.Fatalf("modifying user behind Terraform's back: %s", err)
+					}
+				},
+				Config:             testAccUserConfig("operators", "n3w-s3cret", "jdoe@example.com"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserConfig("operators", "n3w-s3cret", "jdoe@example.com"),
+				Check:  resource.TestCheckResourceAttr("eon_user.test", "user_group", "tfacc-operators"),
+			},
+		},
+	})
+}
+
+func TestAccUser_writeOnlyPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks:   []tfversion.TerraformVersionCheck{tfversion.SkipBelow(tfversion.Version1_11_0)},
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserWriteOnlyConfig(`password = "s3cret"` + "\n" + `password_wo = "s3cret"`),
+				ExpectError: regexp.MustCompile(`Conflicting attributes`),
+			},
+			{
+				Config: testAccUserWriteOnlyConfig(`password_wo = "s3cret"` + "\n" + `password_wo_version = 1`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("eon_user.test", "password_wo"),
+					resource.TestCheckNoResourceAttr("eon_user.test", "password"),
+					resource.TestCheckResourceAttr("eon_user.test", "password_wo_version", "1"),
+					testAccCheckUserPassword("tfacc-wo", "s3cret"),
+				),
+			},
+			{
+				// Without a new version the changed value is not sent.
+				Config:   testAccUserWriteOnlyConfig(`password_wo = "n3w-s3cret"` + "\n" + `password_wo_version = 1`),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccUserWriteOnlyConfig(`password_wo = "n3w-s3cret"` + "\n" + `password_wo_version = 2`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("eon_user.test", "password_wo"),
+					testAccCheckUserPassword("tfacc-wo", "n3w-s3cret"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_ldap(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserLDAPConfig(`ldap_dn = ""`),
+				ExpectError: regexp.MustCompile(`Missing LDAP DN`),
+			},
+			{
+				Config:      testAccUserLDAPConfig(`ldap_dn = "uid=asmith,ou=people,dc=example,dc=com"` + "\n  password = \"x\""),
+				ExpectError: regexp.MustCompile(`Password of an ldap user`),
+			},
+			{
+				Config: testAccUserLDAPConfig(`ldap_dn = "uid=asmith,ou=people,dc=example,dc=com"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eon_user.ldap", "auth_type", "ldap"),
+					resource.TestCheckResourceAttr("eon_user.ldap", "linked_contact", "false"),
+					resource.TestCheckNoResourceAttr("eon_user.ldap", "password"),
+				),
+			},
+			{
+				ResourceName:      "eon_user.ldap",
+				ImportState:       true,
+				ImportStateId:     "tfacc-asmith",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccCheckUserPassword checks the password hash stored by the local
+// server; a real EON never returns it.
+func testAccCheckUserPassword(name, password string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if testAccServer == nil {
+			return nil
+		}
+		want := fmt.Sprintf("%x", md5.Sum([]byte(password)))
+		if got := testAccServer.Get(eontest.Users, name)["user_passwd"]; got != want {
+			return fmt.Errorf("user %s password hash = %v, want %s", name, got, want)
+		}
+		return nil
+	}
+}
+
+func TestAccUser_fieldsOmitted(t *testing.T) {
+	testAccLocalOnly(t)
+	config := testAccUserLDAPConfig(`ldap_dn        = "uid=asmith,ou=people,dc=example,dc=com"
+  mail           = "asmith@example.com"
+  linked_contact = true`)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckLinkedContact("tfacc-asmith", "asmith@example.com"),
+			},
+			{
+				// A user read without user_type and nagios_contact keeps its
+				// auth_type and is not replaced over linked_contact.
+				PreConfig: func() {
+					testAccServer.Omit("getUser", "user_type", "nagios_contact")
+					t.Cleanup(func() { testAccServer.Omit("getUser") })
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// testAccCheckLinkedContact checks that EON created the user's contact.
+func testAccCheckLinkedContact(name, mail string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		r, err := testAccClient().GetContact(name)
+		if err != nil {
+			return fmt.Errorf("linked contact %s: %s", name, err)
+		}
+		if c := client.DecodeContact(client.FirstObject(r)); c.Mail != mail {
+			return fmt.Errorf("linked contact %s mail = %q, want %q", name, c.Mail, mail)
+		}
+		return nil
+	}
+}
+
+func testAccUserConfig(group, password, mail string) string {
+	pw := "null"
+	if password != "" {
+		pw = fmt.Sprintf("%q", password)
+	}
+	return fmt.Sprintf(`
+resource "eon_user_group" "admins" {
+  name = "tfacc-admins"
+}
+
+resource "eon_user_group" "operators" {
+  name = "tfacc-operators"
+}
+
+resource "eon_user" "test" {
+  name           = "tfacc-jdoe"
+  description    = "John Doe"
+  mail           = %[3]q
+  user_group     = eon_user_group.%[1]s.name
+  password       = %[2]s
+  linked_contact = true
+}
+`, group, pw, mail)
+}
+
+func testAccUserWriteOnlyConfig(attrs string) string {
+	return fmt.Sprintf(`
+resource "eon_user_group" "test" {
+  name = "tfacc-wo-users"
+}
+
+resource "eon_user" "test" {
+  name       = "tfacc-wo"
+  user_group = eon_user_group.test.name
+  %s
+}
+`, attrs)
+}
+
+func testAccUserLDAPConfig(attrs string) string {
+	return fmt.Sprintf(`
+resource "eon_user_group" "test" {
+  name       = "tfacc-ldap-users"
+  ldap_group = "cn=monitoring,ou=groups,dc=example,dc=com"
+}
+
+resource "eon_user" "ldap" {
+  name       = "tfacc-asmith"
+  user_group = eon_user_group.test.name
+  auth_type  = "ldap"
+  %s
+}
+`, attrs)
+}
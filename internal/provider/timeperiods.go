@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Nagios timeperiods map weekdays and date exceptions to comma-separated
+// time ranges ("09:00-12:00,13:00-17:00"). Exceptions take one of the
+// forms below, optionally as a range ("a - b") with a skip interval
+// ("/ n"):
+//
+//	2024-12-25              calendar date
+//	december 25             month date
+//	day 1, day -1           day of every month
+//	monday 3, monday -1     nth weekday of every month
+//	thursday 4 november     nth weekday of a month
+//
+// e.g. "day 1 - 15", "2024-01-01 - 2024-03-31 / 7", "july 10 - 15".
+
+var weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+var months = []string{
+	"january", "february", "march", "april", "may", "june",
+	"july", "august", "september", "october", "november", "december",
+}
+
+var timeRangeRe = regexp.MustCompile(`^(\d{1,2}):(\d{2})-(\d{1,2}):(\d{2})$`)
+
+// checkTimeRanges validates a comma-separated list of HH:MM-HH:MM ranges;
+// like Nagios it accepts single-digit hours (9:00). 24:00 may only end a
+// range; "00:00-00:00" means no time at all.
+func checkTimeRanges(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return fmt.Errorf("empty time range; use 00:00-00:00 for none")
+	}
+	for _, r := range strings.Split(s, ",") {
+		r = strings.TrimSpace(r)
+		m := timeRangeRe.FindStringSubmatch(r)
+		if m == nil {
+			return fmt.Errorf("%q is not a HH:MM-HH:MM time range", r)
+		}
+		start, err := minutes(m[1], m[2], false)
+		if err != nil {
+			return fmt.Errorf("%q: %s", r, err)
+		}
+		end, err := minutes(m[3], m[4], true)
+		if err != nil {
+			return fmt.Errorf("%q: %s", r, err)
+		}
+		if end < start {
+			return fmt.Errorf("%q ends before it starts; split ranges crossing midnight at 24:00", r)
+		}
+	}
+	return nil
+}
+
+func minutes(hh, mm string, end bool) (int, error) {
+	h, _ := strconv.Atoi(hh)
+	m, _ := strconv.Atoi(mm)
+	switch {
+	case m > 59:
+		return 0, fmt.Errorf("minutes out of range")
+	case h == 24 && m == 0 && end:
+	case h > 23:
+		return 0, fmt.Errorf("hours out of range (24:00 may only end a range)")
+	}
+	return h*60 + m, nil
+}
+
+var (
+	calendarDateRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	monthDateRe    = regexp.MustCompile(`^(` + strings.Join(months, "|") + `) (-?\d{1,2})$`)
+	monthDayRe     = regexp.MustCompile(`^day (-?\d{1,2})$`)
+	weekdayOffRe   = regexp.MustCompile(`^(` + strings.Join(weekdays, "|") + `) (-?\d)( (` + strings.Join(months, "|") + `))?$`)
+	dayNumberRe    = regexp.MustCompile(`^-?\d{1,2}$`)
+	skipRe         = regexp.MustCompile(`^(.*?) ?/ ?([1-9]\d*)$`)
+)
+
+// checkDateException validates the date part of a timeperiod exception.
+// Weekday names alone are not exceptions: they belong in weekdays. Like
+// weekdays, month and weekday names must be lower case: EON stores the key
+// as written and reports it back that way.
+func checkDateException(s string) error {
+	if s != strings.ToLower(s) {
+		return fmt.Errorf("%q: use lower-case month and weekday names", s)
+	}
+	spec := strings.Join(strings.Fields(s), " ")
+	if m := skipRe.FindStringSubmatch(spec); m != nil {
+		spec = m[1]
+	}
+	from, to, isRange := strings.Cut(spec, " - ")
+	if from == "" {
+		return fmt.Errorf("%q is not a Nagios date exception", s)
+	}
+	kind, err := dateKind(from)
+	if err != nil {
+		return fmt.Errorf("%q: %s", s, err)
+	}
+	if isRange && (kind == "month date" || kind == "month day") && dayNumberRe.MatchString(to) {
+		// "day 1 - 15", "july 10 - 15": the end repeats the month.
+		if err := checkDayOfMonth(to); err != nil {
+			return fmt.Errorf("%q: %s", s, err)
+		}
+	} else if isRange {
+		toKind, err := dateKind(to)
+		if err != nil {
+			return fmt.Errorf("%q: %s", s, err)
+		}
+		if toKind != kind {
+			return fmt.Errorf("%q: both ends of a range must use the same date form", s)
+		}
+	}
+	return nil
+}
+
+// dateKind names the form of a single exception date.
+func dateKind(d string) (string, error) {
+	switch {
+	case calendarDateRe.MatchString(d):
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return "", fmt.Errorf("invalid calendar date %s", d)
+		}
+		return "calendar date", nil
+	case monthDateRe.MatchString(d):
+		return "month date", checkDayOfMonth(monthDateRe.FindStringSubmatch(d)[2])
+	case monthDayRe.MatchString(d):
+		return "month day", checkDayOfMonth(monthDayRe.FindStringSubmatch(d)[1])
+	case weekdayOffRe.MatchString(d):
+		m := weekdayOffRe.FindStringSubmatch(d)
+		if n, _ := strconv.Atoi(m[2]); n == 0 || n < -5 || n > 5 {
+			return "", fmt.Errorf("weekday offset %s must be 1 to 5 or -1 to -5", m[2])
+		}
+		if m[4] != "" {
+			return "month weekday", nil
+		}
+		return "weekday", nil
+	case containsString(weekdays, d):
+		return "", fmt.Errorf("%s is a weekday; set it in weekdays instead", d)
+	}
+	return "", fmt.Errorf("%q is not a Nagios date exception (e.g. 2024-12-25, december 25, day 1, monday 3, thursday -1 november)", d)
+}
+
+func checkDayOfMonth(s string) error {
+	if n, _ := strconv.Atoi(s); n == 0 || n < -31 || n > 31 {
+		return fmt.Errorf("day %s must be 1 to 31 or -1 to -31", s)
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// commandLineValidator checks the Nagios macros of a command line: syntax,
+// unknown standard macros, $ARGn$/$USERn$ ranges, and gaps in $ARGn$
+// numbering. $USERn$ references are checked against the provider's
+// resource_macros at plan time, see commandResource.ModifyPlan.
+type commandLineValidator struct{}
+
+func (commandLineValidator) Description(context.Context) string {
+	return "Nagios macros must be well formed and known."
+}
+
+func (v commandLineValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (commandLineValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	names, err := parseMacros(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid macro", err.Error())
+		return
+	}
+
+	args := map[int]bool{}
+	maxArg := 0
+	for _, name := range names {
+		switch {
+		case strings.HasPrefix(name, "_"):
+			// Custom variable macro, defined on the object.
+		case argMacroRe.MatchString(name):
+			n := macroIndex(argMacroRe, name)
+			if n < 1 || n > maxArgMacro {
+				resp.Diagnostics.AddAttributeError(req.Path, "Invalid macro",
+					fmt.Sprintf("$%s$ is out of range: Nagios passes $ARG1$ to $ARG%d$.", name, maxArgMacro))
+				continue
+			}
+			args[n] = true
+			if n > maxArg {
+				maxArg = n
+			}
+		case userMacroRe.MatchString(name):
+			if n := macroIndex(userMacroRe, name); n < 1 || n > maxUserMacro {
+				resp.Diagnostics.AddAttributeError(req.Path, "Invalid macro",
+					fmt.Sprintf("$%s$ is out of range: Nagios defines $USER1$ to $USER%d$.", name, maxUserMacro))
+			}
+		case !standardMacros[name]:
+			resp.Diagnostics.AddAttributeError(req.Path, "Unknown macro",
+				fmt.Sprintf("$%s$ is not a standard Nagios macro; Nagios would leave it unexpanded.", name))
+		}
+	}
+
+	var missing []string
+	for n := 1; n < maxArg; n++ {
+		if !args[n] {
+			missing = append(missing, fmt.Sprintf("$ARG%d$", n))
+		}
+	}
+	if len(missing) > 0 {
+		resp.Diagnostics.AddAttributeWarning(req.Path, "Unused command arguments",
+			fmt.Sprintf("The command line uses $ARG%d$ but not %s; check_command arguments are positional, "+
+				"so the matching values are silently ignored.", maxArg, strings.Join(missing, ", ")))
+	}
+}
+
+// undefinedUserMacros returns the $USERn$ macros referenced in line that are
+// not in defined, sorted and without duplicates.
+func undefinedUserMacros(line string, defined []string) []string {
+	names, err := parseMacros(line)
+	if err != nil {
+		return nil
+	}
+	known := toSet(defined)
+	seen := map[string]bool{}
+	var out []string
+	for _, name := range names {
+		if userMacroRe.MatchString(name) && !known[name] && !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// nagiosIllegalNameChars is the default illegal_object_name_chars of
+// nagios.cfg, plus ';' which starts a comment in object definitions.
+const nagiosIllegalNameChars = "`~!$%^&*|'\"<>?,()=;"
+
+// objectNameValidator enforces the Nagios object name rules: no illegal
+// characters, no control characters, no leading or trailing whitespace.
+type objectNameValidator struct{}
+
+func (objectNameValidator) Description(context.Context) string {
+	return "Must be a valid Nagios object name."
+}
+
+func (v objectNameValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (objectNameValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if err := checkObjectName(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Nagios object name", err.Error())
+	}
+}
+
+func checkObjectName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("name must not be empty")
+	case strings.TrimSpace(name) != name:
+		return fmt.Errorf("%q has leading or trailing whitespace", name)
+	}
+	for _, c := range name {
+		if c < 0x20 || c == 0x7f {
+			return fmt.Errorf("%q contains a control character", name)
+		}
+		if strings.ContainsRune(nagiosIllegalNameChars, c) {
+			return fmt.Errorf("%q contains %q; Nagios object names cannot contain any of %s", name, c, nagiosIllegalNameChars)
+		}
+	}
+	return nil
+}
+
+var hostnameLabelRe = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+// addressValidator accepts an IPv4/IPv6 address or a DNS host name.
+type addressValidator struct{}
+
+func (addressValidator) Description(context.Context) string {
+	return "Must be an IP address or a fully qualified domain name."
+}
+
+func (v addressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (addressValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if s := req.ConfigValue.ValueString(); !isIP(s) && !isHostname(s) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid address",
+			fmt.Sprintf("%q is neither an IP address nor a valid DNS name.", s))
+	}
+}
+
+func isIP(s string) bool { return net.ParseIP(s) != nil }
+
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	labels := strings.Split(s, ".")
+	for _, l := range labels {
+		if !hostnameLabelRe.MatchString(l) {
+			return false
+		}
+	}
+	// An all-numeric last label is a malformed IPv4 address, not a name.
+	last := labels[len(labels)-1]
+	return strings.Trim(last, "0123456789") != ""
+}
+
+// emailValidator accepts a bare RFC 5322 address (no display name), as
+// Nagios puts it verbatim into $CONTACTEMAIL$.
+type emailValidator struct{}
+
+func (emailValidator) Description(context.Context) string {
+	return "Must be an email address."
+}
+
+func (v emailValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (emailValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	s := req.ConfigValue.ValueString()
+	addr, err := mail.ParseAddress(s)
+	switch {
+	case err != nil:
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid email address", fmt.Sprintf("%q: %s.", s, err))
+	case addr.Name != "" || strings.ContainsAny(s, "<>") || strings.TrimSpace(s) != s:
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid email address",
+			fmt.Sprintf("%q must be a bare address such as %q, without a display name or angle brackets.", s, addr.Address))
+	}
+}
+
+// objectNamesValidator applies objectNameValidator to every element of a
+// set or list of object names.
+type objectNamesValidator struct{}
+
+func (objectNamesValidator) Description(context.Context) string {
+	return "Every element must be a valid Nagios object name."
+}
+
+func (v objectNamesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (objectNamesValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	resp.Diagnostics.Append(checkObjectNames(req.ConfigValue.Elements(), req.Path)...)
+}
+
+func (objectNamesValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	resp.Diagnostics.Append(checkObjectNames(req.ConfigValue.Elements(), req.Path)...)
+}
+
+func checkObjectNames(elems []attr.Value, p path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, e := range elems {
+		s, ok := e.(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+		if err := checkObjectName(s.ValueString()); err != nil {
+			diags.AddAttributeError(p, "Invalid Nagios object name", err.Error())
+		}
+	}
+	return diags
+}
+
+// Notification option letters accepted by Nagios contacts; "n" (none) must
+// stand alone.
+const (
+	hostNotificationOptions    = "durfs"
+	serviceNotificationOptions = "wucrfs"
+)
+
+// notificationOptionsValidator checks a set of Nagios notification option
+// letters against allowed.
+type notificationOptionsValidator struct{ allowed string }
+
+func (v notificationOptionsValidator) Description(context.Context) string {
+	return fmt.Sprintf("Each option must be one of %s, or n alone.", strings.Join(strings.Split(v.allowed, ""), ", "))
+}
+
+func (v notificationOptionsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v notificationOptionsValidator) ValidateSet(_ context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	elems := req.ConfigValue.Elements()
+	for _, e := range elems {
+		s, ok := e.(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+		o := s.ValueString()
+		switch {
+		case o == "n" && len(elems) > 1:
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid notification options",
+				`"n" (no notifications) cannot be combined with other options.`)
+		case o != "n" && (len(o) != 1 || !strings.Contains(v.allowed, o)):
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid notification options",
+				fmt.Sprintf("%q is not a notification option; use one of %s, or n alone.",
+					o, strings.Join(strings.Split(v.allowed, ""), ", ")))
+		}
+	}
+}
+
+// timeRangesValidator checks a map of Nagios timeperiod entries: keys are
+// weekday names, or date exceptions when exceptions is set, and values are
+// time ranges.
+type timeRangesValidator struct{ exceptions bool }
+
+func (v timeRangesValidator) Description(context.Context) string {
+	if v.exceptions {
+		return "Keys must be Nagios date exceptions and values HH:MM-HH:MM time ranges."
+	}
+	return "Keys must be weekday names and values HH:MM-HH:MM time ranges."
+}
+
+func (v timeRangesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v timeRangesValidator) ValidateMap(_ context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	keys := make([]string, 0, len(req.ConfigValue.Elements()))
+	for k := range req.ConfigValue.Elements() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := req.Path.AtMapKey(k)
+		switch {
+		case v.exceptions:
+			if err := checkDateException(k); err != nil {
+				resp.Diagnostics.AddAttributeError(p, "Invalid date exception", err.Error())
+			}
+		case !containsString(weekdays, k):
+			resp.Diagnostics.AddAttributeError(p, "Invalid weekday",
+				fmt.Sprintf("%q is not a weekday; use lower-case names (monday, ...), or exceptions for dates.", k))
+		}
+		s, ok := req.ConfigValue.Elements()[k].(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+		if err := checkTimeRanges(s.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(p, "Invalid time range", err.Error())
+		}
+	}
+}
+
+// customVariableName is a Nagios custom variable name as EON stores it:
+// a leading underscore followed by upper-case letters, digits and
+// underscores.
+var customVariableName = regexp.MustCompile(`^_[A-Z0-9][A-Z0-9_]*$`)
+
+// customVariablesValidator checks a map of Nagios custom variables.
+type customVariablesValidator struct{}
+
+func (v customVariablesValidator) Description(context.Context) string {
+	return "Keys must be upper-case names with a leading underscore (_SNMP_COMMUNITY) and values single lines."
+}
+
+func (v customVariablesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v customVariablesValidator) ValidateMap(_ context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	keys := make([]string, 0, len(req.ConfigValue.Elements()))
+	for k := range req.ConfigValue.Elements() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := req.Path.AtMapKey(k)
+		if !customVariableName.MatchString(k) {
+			resp.Diagnostics.AddAttributeError(p, "Invalid custom variable name",
+				fmt.Sprintf("%q is not a custom variable name; use a leading underscore and upper case, e.g. %q.",
+					k, "_"+strings.TrimLeft(strings.ToUpper(k), "_")))
+		}
+		s, ok := req.ConfigValue.Elements()[k].(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+		if strings.ContainsAny(s.ValueString(), "\r\n") {
+			resp.Diagnostics.AddAttributeError(p, "Invalid custom variable value",
+				fmt.Sprintf("The value of %s must fit on one line.", k))
+		}
+	}
+}
+
+// atLeastValidator checks that a number is at least min.
+type atLeastValidator struct{ min int64 }
+
+func (v atLeastValidator) Description(context.Context) string {
+	return fmt.Sprintf("Must be at least %d.", v.min)
+}
+
+func (v atLeastValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v atLeastValidator) ValidateInt64(_ context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if n := req.ConfigValue.ValueInt64(); n < v.min {
+		resp.Diagnostics.AddAttributeError(req.Path, "Value too small",
+			fmt.Sprintf("%d is less than %d.", n, v.min))
+	}
+}
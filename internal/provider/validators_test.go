@@ -0,0 +1,265 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func validateString(v validator.String, s string) *validator.StringResponse {
+	resp := &validator.StringResponse{}
+	v.ValidateString(context.Background(), validator.StringRequest{
+		Path:        path.Root("test"),
+		ConfigValue: types.StringValue(s),
+	}, resp)
+	return resp
+}
+
+func TestCommandLineValidator(t *testing.T) {
+	for _, tc := range []struct {
+		line, wantErr, wantWarn string
+	}{
+		{line: "$USER1$/check_http -H $HOSTADDRESS$ -p $ARG1$"},
+		{line: "$USER1$/check_snmp -C $_HOSTSNMP_COMMUNITY$ -o $ARG1$"},
+		{line: "/bin/echo $$HOME $SERVICESTATE:web01:HTTP$"},
+		{line: "$USER1$/check_http -H $HOSTADDRES$", wantErr: "$HOSTADDRES$ is not a standard Nagios macro"},
+		{line: "$USER1$/check_http -H $HOSTADDRESS", wantErr: "unterminated macro"},
+		{line: "check -w $ARG1 -c $ARG2$", wantErr: "invalid macro $ARG1 -c $"},
+		{line: "check $ARG33$", wantErr: "$ARG33$ is out of range"},
+		{line: "$USER0$/check", wantErr: "$USER0$ is out of range"},
+		{line: "check -w $ARG1$ -c $ARG3$", wantWarn: "uses $ARG3$ but not $ARG2$"},
+		{line: "check -c $ARG4$ -w $ARG2$", wantWarn: "not $ARG1$, $ARG3$"},
+	} {
+		resp := validateString(commandLineValidator{}, tc.line)
+		errs, warns := resp.Diagnostics.Errors(), resp.Diagnostics.Warnings()
+		switch {
+		case tc.wantErr != "":
+			if len(errs) == 0 || !strings.Contains(errs[0].Detail(), tc.wantErr) {
+				t.Errorf("%q: got errors %v, want %q", tc.line, errs, tc.wantErr)
+			}
+		case len(errs) > 0:
+			t.Errorf("%q: unexpected errors %v", tc.line, errs)
+		}
+		switch {
+		case tc.wantWarn != "":
+			if len(warns) == 0 || !strings.Contains(warns[0].Detail(), tc.wantWarn) {
+				t.Errorf("%q: got warnings %v, want %q", tc.line, warns, tc.wantWarn)
+			}
+		case len(warns) > 0:
+			t.Errorf("%q: unexpected warnings %v", tc.line, warns)
+		}
+	}
+}
+
+func TestUndefinedUserMacros(t *testing.T) {
+	got := undefinedUserMacros("$USER3$/check $USER1$ $USER3$ $USER2$", []string{"USER1"})
+	if want := []string{"USER2", "USER3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("undefinedUserMacros = %v, want %v", got, want)
+	}
+}
+
+func TestObjectNameValidator(t *testing.T) {
+	for name, wantErr := range map[string]bool{
+		"web01":              false,
+		"web-01.example.com": false,
+		"Linux servers":      false,
+		"GENERIC_HOST":       false,
+		"":                   true,
+		" web01":             true,
+		"web01;prod":         true,
+		"check!80":           true,
+		"web`01":             true,
+		"web$01":             true,
+		"line\nbreak":        true,
+		"a<b>":               true,
+		"ops,dev":            true,
+		`quote"d`:            true,
+		"parens(1)":          true,
+		"tilde~":             true,
+		"mgmt=1":             true,
+	} {
+		resp := validateString(objectNameValidator{}, name)
+		if got := resp.Diagnostics.HasError(); got != wantErr {
+			t.Errorf("%q: got error %v, want %v (%v)", name, got, wantErr, resp.Diagnostics)
+		}
+	}
+}
+
+func TestAddressValidator(t *testing.T) {
+	for addr, wantErr := range map[string]bool{
+		"10.0.0.1":           false,
+		"2001:db8::1":        false,
+		"web01":              false,
+		"web-01.example.com": false,
+		"web01.example.com.": false,
+		"10.0.0.256":         true,
+		"10.0.0":             true,
+		"web_01.example.com": true,
+		"-web.example.com":   true,
+		"web..example.com":   true,
+		"http://web01":       true,
+		"":                   true,
+	} {
+		resp := validateString(addressValidator{}, addr)
+		if got := resp.Diagnostics.HasError(); got != wantErr {
+			t.Errorf("%q: got error %v, want %v (%v)", addr, got, wantErr, resp.Diagnostics)
+		}
+	}
+}
+
+func TestEmailValidator(t *testing.T) {
+	for addr, wantErr := range map[string]bool{
+		"ops@example.com":        false,
+		"first.last+tag@ex.org":  false,
+		`"odd name"@example.com`: false,
+		"ops.example.com":        true,
+		"ops@":                   true,
+		"Ops <ops@example.com>":  true,
+		"<ops@example.com>":      true,
+		" ops@example.com":       true,
+		"a@b, c@d":               true,
+	} {
+		resp := validateString(emailValidator{}, addr)
+		if got := resp.Diagnostics.HasError(); got != wantErr {
+			t.Errorf("%q: got error %v, want %v (%v)", addr, got, wantErr, resp.Diagnostics)
+		}
+	}
+}
+
+func TestNotificationOptionsValidator(t *testing.T) {
+	for _, tc := range []struct {
+		allowed string
+		opts    []string
+		wantErr bool
+	}{
+		{allowed: hostNotificationOptions, opts: []string{"d", "u", "r"}},
+		{allowed: hostNotificationOptions, opts: []string{"f", "s"}},
+		{allowed: hostNotificationOptions, opts: []string{"n"}},
+		{allowed: hostNotificationOptions, opts: []string{}},
+		{allowed: serviceNotificationOptions, opts: []string{"w", "u", "c", "r"}},
+		{allowed: hostNotificationOptions, opts: []string{"w"}, wantErr: true},
+		{allowed: serviceNotificationOptions, opts: []string{"d"}, wantErr: true},
+		{allowed: hostNotificationOptions, opts: []string{"n", "d"}, wantErr: true},
+		{allowed: hostNotificationOptions, opts: []string{"du"}, wantErr: true},
+		{allowed: serviceNotificationOptions, opts: []string{"W"}, wantErr: true},
+	} {
+		resp := &validator.SetResponse{}
+		notificationOptionsValidator{allowed: tc.allowed}.ValidateSet(context.Background(), validator.SetRequest{
+			Path:        path.Root("test"),
+			ConfigValue: stringSetValue(tc.opts),
+		}, resp)
+		if got := resp.Diagnostics.HasError(); got != tc.wantErr {
+			t.Errorf("%s %v: got error %v, want %v (%v)", tc.allowed, tc.opts, got, tc.wantErr, resp.Diagnostics)
+		}
+	}
+}
+
+func TestTimeRangesValidator(t *testing.T) {
+	for _, tc := range []struct {
+		exceptions bool
+		key, value string
+		wantErr    string
+	}{
+		{key: "monday", value: "09:00-17:00"},
+		{key: "sunday", value: "00:00-24:00"},
+		{key: "friday", value: "09:00-12:00, 13:00-17:00"},
+		{key: "saturday", value: "00:00-00:00"},
+		{key: "Monday", value: "09:00-17:00", wantErr: "is not a weekday"},
+		{key: "december 25", value: "00:00-24:00", wantErr: "is not a weekday"},
+		{key: "monday", value: "9:00-17:00"},
+		{key: "monday", value: "9:0-17:00", wantErr: "is not a HH:MM-HH:MM time range"},
+		{key: "monday", value: "009:00-17:00", wantErr: "is not a HH:MM-HH:MM time range"},
+		{key: "monday", value: "22:00-02:00", wantErr: "ends before it starts"},
+		{key: "monday", value: "24:00-24:00", wantErr: "hours out of range"},
+		{key: "monday", value: "08:60-09:00", wantErr: "minutes out of range"},
+		{key: "monday", value: "", wantErr: "empty time range"},
+		{exceptions: true, key: "2024-12-25", value: "00:00-00:00"},
+		{exceptions: true, key: "2024-01-01 - 2024-03-31 / 7", value: "08:00-18:00"},
+		{exceptions: true, key: "december 25", value: "00:00-00:00"},
+		{exceptions: true, key: "july 10 - 15", value: "00:00-00:00"},
+		{exceptions: true, key: "february 10 - march 15", value: "00:00-00:00"},
+		{exceptions: true, key: "day 1 - 15", value: "08:00-18:00"},
+		{exceptions: true, key: "day -1", value: "08:00-18:00"},
+		{exceptions: true, key: "monday 3", value: "00:00-24:00"},
+		{exceptions: true, key: "thursday -1 november", value: "00:00-24:00"},
+		{exceptions: true, key: "monday 1 september - monday 1 october", value: "00:00-24:00"},
+		{exceptions: true, key: "2024-02-30", value: "00:00-24:00", wantErr: "invalid calendar date"},
+		{exceptions: true, key: "day 32", value: "00:00-24:00", wantErr: "must be 1 to 31"},
+		{exceptions: true, key: "monday 6", value: "00:00-24:00", wantErr: "must be 1 to 5"},
+		{exceptions: true, key: "monday", value: "00:00-24:00", wantErr: "set it in weekdays"},
+		{exceptions: true, key: "2024-01-01 - day 5", value: "00:00-24:00", wantErr: "same date form"},
+		{exceptions: true, key: "xmas", value: "00:00-24:00", wantErr: "is not a Nagios date exception"},
+		{exceptions: true, key: "December 25", value: "00:00-00:00", wantErr: "use lower-case"},
+		{exceptions: true, key: "Monday 3", value: "00:00-24:00", wantErr: "use lower-case"},
+	} {
+		resp := &validator.MapResponse{}
+		timeRangesValidator{exceptions: tc.exceptions}.ValidateMap(context.Background(), validator.MapRequest{
+			Path:        path.Root("test"),
+			ConfigValue: types.MapValueMust(types.StringType, map[string]attr.Value{tc.key: types.StringValue(tc.value)}),
+		}, resp)
+		errs := resp.Diagnostics.Errors()
+		switch {
+		case tc.wantErr == "" && len(errs) > 0:
+			t.Errorf("%q = %q: unexpected error %v", tc.key, tc.value, errs)
+		case tc.wantErr != "" && (len(errs) == 0 || !strings.Contains(errs[0].Detail(), tc.wantErr)):
+			t.Errorf("%q = %q: got %v, want error containing %q", tc.key, tc.value, errs, tc.wantErr)
+		}
+	}
+}
+
+func TestCustomVariablesValidator(t *testing.T) {
+	for _, tc := range []struct {
+		key, value string
+		wantErr    string
+	}{
+		{key: "_SNMP_COMMUNITY", value: "public"},
+		{key: "_CMDB_ID", value: "CI00042"},
+		{key: "_X", value: ""},
+		{key: "SNMP_COMMUNITY", value: "public", wantErr: `e.g. "_SNMP_COMMUNITY"`},
+		{key: "_snmp_community", value: "public", wantErr: "is not a custom variable name"},
+		{key: "_", value: "x", wantErr: "is not a custom variable name"},
+		{key: "_SNMP COMMUNITY", value: "x", wantErr: "is not a custom variable name"},
+		{key: "_NOTE", value: "two\nlines", wantErr: "must fit on one line"},
+	} {
+		resp := &validator.MapResponse{}
+		customVariablesValidator{}.ValidateMap(context.Background(), validator.MapRequest{
+			Path:        path.Root("test"),
+			ConfigValue: types.MapValueMust(types.StringType, map[string]attr.Value{tc.key: types.StringValue(tc.value)}),
+		}, resp)
+		errs := resp.Diagnostics.Errors()
+		switch {
+		case tc.wantErr == "" && len(errs) > 0:
+			t.Errorf("%q = %q: unexpected error %v", tc.key, tc.value, errs)
+		case tc.wantErr != "" && (len(errs) == 0 || !strings.Contains(errs[0].Detail(), tc.wantErr)):
+			t.Errorf("%q = %q: got %v, want error containing %q", tc.key, tc.value, errs, tc.wantErr)
+		}
+	}
+}
+
+func TestAtLeastValidator(t *testing.T) {
+	for _, tc := range []struct {
+		min, n  int64
+		wantErr bool
+	}{
+		{min: 1, n: 1},
+		{min: 1, n: 10},
+		{min: 0, n: 0},
+		{min: 1, n: 0, wantErr: true},
+		{min: 0, n: -1, wantErr: true},
+	} {
+		resp := &validator.Int64Response{}
+		atLeastValidator{tc.min}.ValidateInt64(context.Background(), validator.Int64Request{
+			Path:        path.Root("test"),
+			ConfigValue: types.Int64Value(tc.n),
+		}, resp)
+		if got := resp.Diagnostics.HasError(); got != tc.wantErr {
+			t.Errorf("atLeast(%d) on %d: error = %v, want %v", tc.min, tc.n, got, tc.wantErr)
+		}
+	}
+}
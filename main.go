@@ -17,4 +17,8 @@ func main() {
 	if err := providerserver.Serve(context.Background(), provider.New(version), opts); err != nil {
 		log.Fatal(err)
 	}
+	// Terraform has stopped the provider: flush auto_export.
+	if err := provider.Shutdown(context.Background()); err != nil {
+		log.Printf("[ERROR] auto_export: %s", err)
+	}
 }